@@ -0,0 +1,3 @@
+module github.com/mtsanderson/nesgo
+
+go 1.23
@@ -1,12 +1,46 @@
 package main
 
-import "flag"
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mtsanderson/nesgo/nes"
+)
+
+func check(e error) {
+	if e != nil {
+		fmt.Println(e)
+		os.Exit(1)
+	}
+}
 
 func main() {
 	romPath := flag.String("rom", "", "Path to ROM file")
+	startPC := flag.Uint("pc", 0, "Start execution at this address instead of the RESET vector (0xC000 for nestest)")
+	tracePath := flag.String("trace", "", "Write a nestest style instruction log to this file")
 	flag.Parse()
 
-	rom := ROM{data: readBinary(*romPath)}
-	nes := NES{rom: rom}
-	nes.powerOn()
+	rom, err := os.ReadFile(*romPath)
+	check(err)
+	opts := nes.Options{StartPC: uint16(*startPC)}
+	var trace *bufio.Writer
+	if *tracePath != "" {
+		f, err := os.Create(*tracePath)
+		check(err)
+		trace = bufio.NewWriter(f)
+		opts.Tracer = nes.NewTracer(trace)
+	}
+	console, err := nes.New(rom, opts)
+	check(err)
+
+	// program loop
+	for {
+		_, _, err := console.RunFrame()
+		if trace != nil {
+			trace.Flush()
+		}
+		check(err)
+	}
 }
@@ -0,0 +1,286 @@
+package nes
+
+//cpuFrequency ... NTSC CPU clock in Hz
+const cpuFrequency = 1789773
+
+//Frame counter step timings in CPU cycles, NTSC
+//Reference: https://wiki.nesdev.com/w/index.php/APU_Frame_Counter
+const (
+	frameStep1     = 7457
+	frameStep2     = 14913
+	frameStep3     = 22371
+	frameStep4     = 29829
+	frameStep5     = 37281
+	frameIRQCycle  = 29828
+	fourStepLength = 29830
+	fiveStepLength = 37282
+)
+
+//APU ... Represents the 2A03 Audio Processing Unit
+//Reference: https://wiki.nesdev.com/w/index.php/APU
+type APU struct {
+	pulse1   pulse
+	pulse2   pulse
+	triangle triangle
+	noise    noise
+	dmc      dmc
+
+	cycle      uint64
+	frameCycle int  //CPU cycles into the frame counter sequence
+	fiveStep   bool //$4017 bit 7, 5-step sequence
+	irqInhibit bool //$4017 bit 6
+	frameIRQ   bool
+
+	sampleRate  float64
+	sampleClock float64
+	samples     []float32
+}
+
+//Mixer lookup tables
+//Reference: https://wiki.nesdev.com/w/index.php/APU_Mixer
+var pulseTable [31]float32
+var tndTable [203]float32
+
+func init() {
+	for i := 1; i < len(pulseTable); i++ {
+		pulseTable[i] = float32(95.52 / (8128.0/float64(i) + 100))
+	}
+	for i := 1; i < len(tndTable); i++ {
+		tndTable[i] = float32(163.67 / (24329.0/float64(i) + 100))
+	}
+}
+
+func newAPU(bus Memory, cpu *CPU, sampleRate float64) *APU {
+	apu := &APU{sampleRate: sampleRate}
+	apu.pulse1.channel = 1
+	apu.pulse2.channel = 2
+	apu.noise.shift = 1
+	apu.dmc.bus = bus
+	apu.dmc.cpu = cpu
+	apu.dmc.period = dmcTable[0]
+	apu.dmc.bitsLeft = 8
+	apu.dmc.silence = true
+	return apu
+}
+
+/*
+===============================================================================
+				CPU Registers ($4000-$4013, $4015, $4017)
+===============================================================================
+*/
+
+//read ... $4015 status, the only readable APU register. The bus returns open
+//bus for the others.
+func (apu *APU) read(addr uint16) byte {
+	var status byte
+	if apu.pulse1.length.value > 0 {
+		status = setBit(status, 0)
+	}
+	if apu.pulse2.length.value > 0 {
+		status = setBit(status, 1)
+	}
+	if apu.triangle.length.value > 0 {
+		status = setBit(status, 2)
+	}
+	if apu.noise.length.value > 0 {
+		status = setBit(status, 3)
+	}
+	if apu.dmc.remaining > 0 {
+		status = setBit(status, 4)
+	}
+	if apu.frameIRQ {
+		status = setBit(status, 6)
+	}
+	if apu.dmc.irq {
+		status = setBit(status, 7)
+	}
+	apu.frameIRQ = false
+	return status
+}
+
+func (apu *APU) write(addr uint16, val byte) {
+	switch {
+	case addr < 0x4004:
+		apu.pulse1.write(addr-0x4000, val)
+	case addr < 0x4008:
+		apu.pulse2.write(addr-0x4004, val)
+	case addr < 0x400C:
+		apu.triangle.write(addr-0x4008, val)
+	case addr < 0x4010:
+		apu.noise.write(addr-0x400C, val)
+	case addr < 0x4014:
+		apu.dmc.write(addr-0x4010, val)
+	case addr == 0x4015:
+		apu.pulse1.length.setEnabled(hasBit(val, 0))
+		apu.pulse2.length.setEnabled(hasBit(val, 1))
+		apu.triangle.length.setEnabled(hasBit(val, 2))
+		apu.noise.length.setEnabled(hasBit(val, 3))
+		apu.dmc.setEnabled(hasBit(val, 4))
+	case addr == 0x4017:
+		apu.fiveStep = hasBit(val, 7)
+		apu.irqInhibit = hasBit(val, 6)
+		if apu.irqInhibit {
+			apu.frameIRQ = false
+		}
+		apu.frameCycle = 0
+		if apu.fiveStep {
+			apu.clockQuarterFrame()
+			apu.clockHalfFrame()
+		}
+	}
+}
+
+/*
+===============================================================================
+				Timing
+===============================================================================
+*/
+
+//step ... Advances the APU by one CPU cycle
+func (apu *APU) step() {
+	apu.cycle++
+	apu.stepFrameCounter()
+	if apu.cycle%2 == 0 {
+		apu.pulse1.stepTimer()
+		apu.pulse2.stepTimer()
+	}
+	apu.triangle.stepTimer()
+	apu.noise.stepTimer()
+	apu.dmc.stepTimer()
+
+	apu.sampleClock += apu.sampleRate
+	if apu.sampleClock >= cpuFrequency {
+		apu.sampleClock -= cpuFrequency
+		apu.samples = append(apu.samples, apu.output())
+	}
+}
+
+func (apu *APU) stepFrameCounter() {
+	apu.frameCycle++
+	switch apu.frameCycle {
+	case frameStep1, frameStep3:
+		apu.clockQuarterFrame()
+	case frameStep2:
+		apu.clockQuarterFrame()
+		apu.clockHalfFrame()
+	case frameIRQCycle:
+		apu.raiseFrameIRQ()
+	case frameStep4:
+		if !apu.fiveStep {
+			apu.clockQuarterFrame()
+			apu.clockHalfFrame()
+			apu.raiseFrameIRQ()
+		}
+	case fourStepLength:
+		if !apu.fiveStep {
+			apu.raiseFrameIRQ()
+			apu.frameCycle = 0
+		}
+	case frameStep5:
+		apu.clockQuarterFrame()
+		apu.clockHalfFrame()
+	case fiveStepLength:
+		apu.frameCycle = 0
+	}
+}
+
+func (apu *APU) raiseFrameIRQ() {
+	if !apu.fiveStep && !apu.irqInhibit {
+		apu.frameIRQ = true
+	}
+}
+
+//clockQuarterFrame ... Envelopes and the triangle linear counter
+func (apu *APU) clockQuarterFrame() {
+	apu.pulse1.envelope.clock()
+	apu.pulse2.envelope.clock()
+	apu.noise.envelope.clock()
+	apu.triangle.clockLinear()
+}
+
+//clockHalfFrame ... Length counters and sweep units
+func (apu *APU) clockHalfFrame() {
+	apu.pulse1.length.clock()
+	apu.pulse2.length.clock()
+	apu.triangle.length.clock()
+	apu.noise.length.clock()
+	apu.pulse1.clockSweep()
+	apu.pulse2.clockSweep()
+}
+
+//irq ... IRQ outputs of the frame counter and the DMC
+func (apu *APU) irq() (frame bool, dmc bool) {
+	return apu.frameIRQ, apu.dmc.irq
+}
+
+/*
+===============================================================================
+				Save State
+===============================================================================
+*/
+
+//apuState ... Serializable APU state. Samples not drained yet are left out.
+type apuState struct {
+	Pulse1     pulseState
+	Pulse2     pulseState
+	Triangle   triangleState
+	Noise      noiseState
+	DMC        dmcState
+	Cycle      uint64
+	FrameCycle int
+	FiveStep   bool
+	IRQInhibit bool
+	FrameIRQ   bool
+
+	SampleClock float64
+}
+
+func (apu *APU) state() apuState {
+	return apuState{
+		Pulse1:      apu.pulse1.state(),
+		Pulse2:      apu.pulse2.state(),
+		Triangle:    apu.triangle.state(),
+		Noise:       apu.noise.state(),
+		DMC:         apu.dmc.state(),
+		Cycle:       apu.cycle,
+		FrameCycle:  apu.frameCycle,
+		FiveStep:    apu.fiveStep,
+		IRQInhibit:  apu.irqInhibit,
+		FrameIRQ:    apu.frameIRQ,
+		SampleClock: apu.sampleClock,
+	}
+}
+
+func (apu *APU) restore(s apuState) {
+	apu.pulse1.restore(s.Pulse1)
+	apu.pulse2.restore(s.Pulse2)
+	apu.triangle.restore(s.Triangle)
+	apu.noise.restore(s.Noise)
+	apu.dmc.restore(s.DMC)
+	apu.cycle = s.Cycle
+	apu.frameCycle = s.FrameCycle
+	apu.fiveStep = s.FiveStep
+	apu.irqInhibit = s.IRQInhibit
+	apu.frameIRQ = s.FrameIRQ
+	apu.sampleClock = s.SampleClock
+}
+
+/*
+===============================================================================
+				Mixer
+===============================================================================
+*/
+
+//output ... Mixes the channels with the non-linear DAC approximation, 0.0-1.0
+func (apu *APU) output() float32 {
+	p := apu.pulse1.output() + apu.pulse2.output()
+	tnd := 3*int(apu.triangle.output()) + 2*int(apu.noise.output()) + int(apu.dmc.output())
+	return pulseTable[p] + tndTable[tnd]
+}
+
+//drainSamples ... Returns the samples produced since the last call
+func (apu *APU) drainSamples() []float32 {
+	samples := apu.samples
+	apu.samples = nil
+	return samples
+}
@@ -0,0 +1,181 @@
+package nes
+
+import "testing"
+
+//TestLengthCounter ... Loads from the length table only while enabled and
+//counts down on half frames unless halted
+func TestLengthCounter(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		halt    bool
+		index   byte
+		clocks  int
+		want    byte
+	}{
+		{name: "load", enabled: true, index: 0x01, want: 254},
+		{name: "counts down", enabled: true, index: 0x00, clocks: 3, want: 7},
+		{name: "stops at zero", enabled: true, index: 0x03, clocks: 3, want: 0},
+		{name: "halted", enabled: true, halt: true, index: 0x00, clocks: 3, want: 10},
+		{name: "disabled ignores loads", index: 0x01, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := lengthCounter{enabled: tt.enabled, halt: tt.halt}
+			l.load(tt.index)
+			for i := 0; i < tt.clocks; i++ {
+				l.clock()
+			}
+			if l.value != tt.want {
+				t.Errorf("value = %d, want %d", l.value, tt.want)
+			}
+		})
+	}
+}
+
+//TestEnvelope ... Volume after each quarter frame clock following a start
+func TestEnvelope(t *testing.T) {
+	tests := []struct {
+		name string
+		reg  byte //--LCVVVV as written to $4000
+		want []byte
+	}{
+		{name: "constant volume", reg: 0x19, want: []byte{9, 9, 9}},
+		{name: "decays every clock", reg: 0x00, want: []byte{15, 14, 13, 12}},
+		{name: "decays every third clock", reg: 0x02, want: []byte{15, 15, 15, 14, 14, 14, 13}},
+		{name: "stops at zero", reg: 0x00, want: append(fifteenDown(), 0, 0)},
+		{name: "loops", reg: 0x20, want: append(fifteenDown(), 15, 14)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e envelope
+			e.write(tt.reg)
+			e.start = true
+			for i, want := range tt.want {
+				e.clock()
+				if got := e.volume(); got != want {
+					t.Fatalf("clock %d: volume = %d, want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+//fifteenDown ... Envelope volumes from 15 down to 0
+func fifteenDown() []byte {
+	volumes := make([]byte, 16)
+	for i := range volumes {
+		volumes[i] = byte(15 - i)
+	}
+	return volumes
+}
+
+//TestSweepMute ... The sweep unit mutes the channel for periods below 8 and
+//targets past $7FF, whether or not the sweep is enabled
+func TestSweepMute(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel byte
+		period  uint16
+		sweep   byte //EPPPNSSS as written to $4001
+		target  uint16
+		muted   bool
+	}{
+		{name: "period below 8", channel: 1, period: 0x007, sweep: 0x01, target: 0x00A, muted: true},
+		{name: "period 8", channel: 1, period: 0x008, sweep: 0x01, target: 0x00C},
+		{name: "target past $7FF", channel: 1, period: 0x600, sweep: 0x01, target: 0x900, muted: true},
+		{name: "target past $7FF, sweep disabled", channel: 2, period: 0x400, sweep: 0x00, target: 0x800, muted: true},
+		{name: "negate, pulse 1", channel: 1, period: 0x100, sweep: 0x09, target: 0x07F},
+		{name: "negate, pulse 2", channel: 2, period: 0x100, sweep: 0x09, target: 0x080},
+		{name: "negate never mutes", channel: 2, period: 0x7FF, sweep: 0x08, target: 0x000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := pulse{channel: tt.channel, period: tt.period}
+			p.write(1, tt.sweep)
+			if got := p.sweepTarget(); got != tt.target {
+				t.Errorf("target = $%03X, want $%03X", got, tt.target)
+			}
+			if p.muted() != tt.muted {
+				t.Errorf("muted = %v, want %v", p.muted(), tt.muted)
+			}
+		})
+	}
+}
+
+//TestFrameIRQ ... The 4-step sequence raises the frame IRQ on its last cycles
+//unless inhibited, the 5-step sequence never does
+func TestFrameIRQ(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame byte //$4017
+		want  bool
+	}{
+		{name: "4-step", frame: 0x00, want: true},
+		{name: "4-step, inhibited", frame: 0x40},
+		{name: "5-step", frame: 0x80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apu := newAPU(nil, &CPU{}, defaultSampleRate)
+			apu.write(0x4017, tt.frame)
+			for i := 0; i < frameIRQCycle-1; i++ {
+				apu.step()
+			}
+			if frame, _ := apu.irq(); frame {
+				t.Fatal("frame IRQ raised early")
+			}
+			apu.step()
+			if frame, _ := apu.irq(); frame != tt.want {
+				t.Fatalf("frame IRQ = %v, want %v", frame, tt.want)
+			}
+			if !tt.want {
+				return
+			}
+			apu.read(apuStatus)
+			if frame, _ := apu.irq(); frame {
+				t.Error("reading $4015 didn't acknowledge the frame IRQ")
+			}
+		})
+	}
+}
+
+//TestFrameIRQInhibit ... Setting the inhibit flag clears a pending frame IRQ
+func TestFrameIRQInhibit(t *testing.T) {
+	apu := newAPU(nil, &CPU{}, defaultSampleRate)
+	for i := 0; i < frameIRQCycle; i++ {
+		apu.step()
+	}
+	apu.write(0x4017, 0x40)
+	if frame, _ := apu.irq(); frame {
+		t.Error("frame IRQ still raised")
+	}
+}
+
+//TestAPUStatus ... $4015 reports the length counters, DMC bytes remaining and
+//both IRQ flags
+func TestAPUStatus(t *testing.T) {
+	apu := newAPU(nil, &CPU{}, defaultSampleRate)
+	apu.write(apuStatus, 0x1F)
+	apu.write(0x4003, 0x08) //pulse 1
+	apu.write(0x400F, 0x08) //noise
+	apu.write(0x4013, 0x01) //DMC sample length 17
+	apu.write(apuStatus, 0x1F)
+	apu.frameIRQ = true
+	apu.dmc.irq = true
+
+	if got := apu.read(apuStatus); got != 0xD9 {
+		t.Errorf("status = $%02X, want $D9", got)
+	}
+	if got := apu.read(apuStatus); got != 0x99 {
+		t.Errorf("status = $%02X after a read, want $99", got)
+	}
+	apu.write(apuStatus, 0x00)
+	if got := apu.read(apuStatus); got != 0x00 {
+		t.Errorf("status = $%02X with all channels disabled, want $00", got)
+	}
+}
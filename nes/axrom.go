@@ -0,0 +1,79 @@
+package nes
+
+import "io"
+
+//AxROM ... Mapper 7, switchable 32KB PRG bank and single-screen mirroring
+//Reference: https://wiki.nesdev.com/w/index.php/AxROM
+type AxROM struct {
+	board
+	bank byte //xxxMxPPP, M selects the nametable, PPP the PRG bank
+}
+
+const axromBankSize int = 32 * kbSize
+
+func newAxROM(rom *ROM) *AxROM {
+	m := &AxROM{board: newBoard(rom)}
+	m.busConflicts = hasBusConflicts(rom)
+	m.mirror = MirrorSingleLower
+	return m
+}
+
+func (m *AxROM) cpuRead(addr uint16) byte {
+	if addr < 0x8000 {
+		return 0
+	}
+	banks := len(m.prgROM) / axromBankSize
+	if banks == 0 {
+		return m.prgROM[int(addr-0x8000)%len(m.prgROM)]
+	}
+	return m.prgROM[int(m.bank&0x07)%banks*axromBankSize+int(addr-0x8000)]
+}
+
+func (m *AxROM) cpuWrite(addr uint16, val byte) {
+	if addr < 0x8000 {
+		return
+	}
+	m.bank = m.conflict(m, addr, val)
+	if hasBit(m.bank, 4) {
+		m.mirror = MirrorSingleUpper
+	} else {
+		m.mirror = MirrorSingleLower
+	}
+}
+
+func (m *AxROM) ppuRead(addr uint16) byte {
+	return m.chr[int(addr)%len(m.chr)]
+}
+
+func (m *AxROM) ppuWrite(addr uint16, val byte) {
+	if m.chrRAM {
+		m.chr[int(addr)%len(m.chr)] = val
+	}
+}
+
+func (m *AxROM) mirroring() Mirroring {
+	return m.mirror
+}
+
+func (m *AxROM) irq() bool {
+	return false
+}
+
+type axromState struct {
+	Board boardState
+	Bank  byte
+}
+
+func (m *AxROM) saveState(w io.Writer) error {
+	return encodeState(w, axromState{Board: m.state(), Bank: m.bank})
+}
+
+func (m *AxROM) loadState(r io.Reader) error {
+	var s axromState
+	if err := decodeState(r, &s); err != nil {
+		return err
+	}
+	m.restore(s.Board)
+	m.bank = s.Bank
+	return nil
+}
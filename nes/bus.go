@@ -0,0 +1,106 @@
+package nes
+
+//Bus ... CPU address bus, routes reads and writes through the NES memory map
+//Reference: https://wiki.nesdev.com/w/index.php/CPU_memory_map
+//
+//$0000-$1FFF	2KB internal RAM, mirrored four times
+//$2000-$3FFF	PPU registers, mirrored every 8 bytes
+//$4000-$4017	APU and I/O registers
+//$4018-$401F	APU and I/O test functionality (normally disabled)
+//$4020-$FFFF	Cartridge space: PRG ROM, PRG RAM and mapper registers
+type Bus struct {
+	ram  RAM
+	ppu  Memory
+	apu  Memory
+	cart Mapper
+	cpu  *CPU //Stalled by DMA
+
+	controllers [2]Controller
+	latch       byte //Last value on the data bus, seen in open bus bits
+}
+
+const oamDMA uint16 = 0x4014
+const apuStatus uint16 = 0x4015
+const joypad1 uint16 = 0x4016
+const joypad2 uint16 = 0x4017
+
+func newBus(ppu Memory, cart Mapper) *Bus {
+	return &Bus{
+		ppu:  ppu,
+		cart: cart,
+	}
+}
+
+func (b *Bus) read(addr uint16) byte {
+	b.latch = b.readAddress(addr)
+	return b.latch
+}
+
+func (b *Bus) readAddress(addr uint16) byte {
+	switch {
+	case addr < 0x2000:
+		return b.ram.read(addr)
+	case addr < 0x4000:
+		return b.ppu.read(addr)
+	case addr == joypad1, addr == joypad2:
+		// only the low bits are driven, the rest is open bus
+		return (b.latch & 0xE0) | b.controllers[addr-joypad1].read()
+	case addr == apuStatus:
+		// bit 5 isn't driven and reads as open bus
+		return (b.latch & 0x20) | b.apu.read(addr)
+	case addr < 0x4018:
+		// the other APU registers are write-only
+		return b.latch
+	case addr < 0x4020:
+		return 0
+	default:
+		return b.cart.cpuRead(addr)
+	}
+}
+
+//peek ... Reads memory without side effects for debugging. I/O registers can
+//change state when read so they show the open bus value instead.
+func (b *Bus) peek(addr uint16) byte {
+	switch {
+	case addr < 0x2000:
+		return b.ram.read(addr)
+	case addr < 0x4020:
+		return b.latch
+	default:
+		return b.cart.cpuRead(addr)
+	}
+}
+
+func (b *Bus) write(addr uint16, val byte) {
+	b.latch = val
+	switch {
+	case addr < 0x2000:
+		b.ram.write(addr, val)
+	case addr < 0x4000:
+		b.ppu.write(addr, val)
+	case addr == oamDMA:
+		b.writeOAMDMA(val)
+	case addr == joypad1:
+		b.controllers[0].write(val)
+		b.controllers[1].write(val)
+	case addr < 0x4018:
+		b.apu.write(addr, val)
+	case addr < 0x4020:
+		// test mode registers, ignored
+	default:
+		b.cart.cpuWrite(addr, val)
+	}
+}
+
+//writeOAMDMA ... Copies page $XX00-$XXFF into PPU OAM through $2004. The CPU is
+//halted for 513 cycles, plus one alignment cycle if $4014 is written on an odd
+//cycle. The last 512 read a byte and write it to $2004 in turn.
+//Reference: https://wiki.nesdev.com/w/index.php/PPU_registers#OAMDMA
+func (b *Bus) writeOAMDMA(page byte) {
+	cycles := 1
+	if b.cpu.accessCycle()%2 == 1 {
+		cycles++
+	}
+	b.cpu.stall(cycles)
+	b.cpu.startOAMDMA(page)
+}
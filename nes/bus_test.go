@@ -0,0 +1,76 @@
+package nes
+
+import "testing"
+
+//TestOAMDMAStall ... The alignment cycle depends on the parity of the cycle
+//$4014 is written on, not of the cycle the writing instruction started on
+func TestOAMDMAStall(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup []byte //Run before the store, the first instruction sets A and X
+		store []byte
+		write uint64 //Cycle of the store's write, counted from its first cycle
+	}{
+		{name: "STA abs", setup: []byte{0xA9, 0x02}, store: []byte{0x8D, 0x14, 0x40}, write: 3},
+		{name: "STA abs, odd start", setup: []byte{0xA9, 0x02, 0xA5, 0x00}, store: []byte{0x8D, 0x14, 0x40}, write: 3},
+		{name: "STA abs,X", setup: []byte{0xA9, 0x02}, store: []byte{0x9D, 0x14, 0x40}, write: 4},
+		{name: "STA abs,X, odd start", setup: []byte{0xA9, 0x02, 0xA5, 0x00}, store: []byte{0x9D, 0x14, 0x40}, write: 4},
+	}
+
+	for _, tt := range tests {
+		for _, cycleStepped := range []bool{false, true} {
+			name := tt.name
+			if cycleStepped {
+				name += ", cycle-stepped"
+			}
+			t.Run(name, func(t *testing.T) {
+				prg := append(append(append([]byte{}, tt.setup...), tt.store...), 0xEA)
+				console, err := New(nromImage(prg, 0xC000), Options{CycleStepped: cycleStepped})
+				if err != nil {
+					t.Fatal(err)
+				}
+				store := 0xC000 + uint16(len(tt.setup))
+				for console.CPU().PC != store {
+					if _, err := console.StepInstruction(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				want := 513 + int((console.CPU().Cycles+tt.write)%2)
+				if _, err := console.StepInstruction(); err != nil {
+					t.Fatal(err)
+				}
+
+				stall := 0
+				for {
+					cycles, err := console.StepInstruction()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if cycles != 1 {
+						break
+					}
+					stall++
+				}
+				if stall != want {
+					t.Errorf("stalled %d cycles, want %d", stall, want)
+				}
+			})
+		}
+	}
+}
+
+//TestAPUOpenBus ... The write-only APU registers read back the last value on
+//the data bus, and so does bit 5 of $4015
+func TestAPUOpenBus(t *testing.T) {
+	b := newBus(nil, nil)
+	b.apu = newAPU(b, &CPU{}, defaultSampleRate)
+	b.latch = 0x7F
+	for _, addr := range []uint16{0x4000, 0x4008, 0x4013} {
+		if got := b.read(addr); got != 0x7F {
+			t.Errorf("$%04X reads $%02X, want $7F", addr, got)
+		}
+	}
+	if got := b.read(apuStatus); got != 0x20 {
+		t.Errorf("$4015 reads $%02X, want $20", got)
+	}
+}
@@ -0,0 +1,642 @@
+package nes
+
+//Reference: https://wiki.nesdev.com/w/index.php/APU
+
+var lengthTable = [32]byte{
+	10, 254, 20, 2, 40, 4, 80, 6, 160, 8, 60, 10, 14, 12, 26, 14,
+	12, 16, 24, 18, 48, 20, 96, 22, 192, 24, 72, 26, 16, 28, 32, 30,
+}
+
+var dutyTable = [4][8]byte{
+	{0, 1, 0, 0, 0, 0, 0, 0},
+	{0, 1, 1, 0, 0, 0, 0, 0},
+	{0, 1, 1, 1, 1, 0, 0, 0},
+	{1, 0, 0, 1, 1, 1, 1, 1},
+}
+
+var triangleTable = [32]byte{
+	15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0,
+	0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+}
+
+//noiseTable ... Noise timer periods in CPU cycles, NTSC
+var noiseTable = [16]uint16{
+	4, 8, 16, 32, 64, 96, 128, 160, 202, 254, 380, 508, 762, 1016, 2034, 4068,
+}
+
+//dmcTable ... DMC timer periods in CPU cycles, NTSC
+var dmcTable = [16]uint16{
+	428, 380, 340, 320, 286, 254, 226, 214, 190, 160, 142, 128, 106, 84, 72, 54,
+}
+
+/*
+===============================================================================
+				Shared Units
+===============================================================================
+*/
+
+//lengthCounter ... Silences a channel once it counts down to zero
+type lengthCounter struct {
+	enabled bool
+	halt    bool
+	value   byte
+}
+
+func (l *lengthCounter) load(index byte) {
+	if l.enabled {
+		l.value = lengthTable[index&0x1F]
+	}
+}
+
+func (l *lengthCounter) setEnabled(enabled bool) {
+	l.enabled = enabled
+	if !enabled {
+		l.value = 0
+	}
+}
+
+//clock ... Half frame clock
+func (l *lengthCounter) clock() {
+	if !l.halt && l.value > 0 {
+		l.value--
+	}
+}
+
+//envelope ... Volume envelope generator, or a constant volume
+type envelope struct {
+	start    bool
+	loop     bool
+	constant bool
+	period   byte //Divider period, also the constant volume
+	divider  byte
+	decay    byte
+}
+
+func (e *envelope) write(val byte) {
+	e.loop = hasBit(val, 5)
+	e.constant = hasBit(val, 4)
+	e.period = val & 0x0F
+}
+
+//clock ... Quarter frame clock
+func (e *envelope) clock() {
+	if e.start {
+		e.start = false
+		e.decay = 15
+		e.divider = e.period
+		return
+	}
+	if e.divider > 0 {
+		e.divider--
+		return
+	}
+	e.divider = e.period
+	if e.decay > 0 {
+		e.decay--
+	} else if e.loop {
+		e.decay = 15
+	}
+}
+
+func (e *envelope) volume() byte {
+	if e.constant {
+		return e.period
+	}
+	return e.decay
+}
+
+/*
+===============================================================================
+				Pulse
+===============================================================================
+*/
+
+//pulse ... Square wave channel with a sweep unit ($4000-$4003, $4004-$4007)
+type pulse struct {
+	channel  byte //1 or 2, the sweep units negate differently
+	length   lengthCounter
+	envelope envelope
+	duty     byte
+	step     byte
+	period   uint16
+	timer    uint16
+
+	sweepEnabled bool
+	sweepPeriod  byte
+	sweepNegate  bool
+	sweepShift   byte
+	sweepDivider byte
+	sweepReload  bool
+}
+
+func (p *pulse) write(reg uint16, val byte) {
+	switch reg {
+	case 0:
+		p.duty = val >> 6
+		p.length.halt = hasBit(val, 5)
+		p.envelope.write(val)
+	case 1:
+		p.sweepEnabled = hasBit(val, 7)
+		p.sweepPeriod = (val >> 4) & 0x07
+		p.sweepNegate = hasBit(val, 3)
+		p.sweepShift = val & 0x07
+		p.sweepReload = true
+	case 2:
+		p.period = (p.period & 0x0700) | uint16(val)
+	case 3:
+		p.period = (p.period & 0x00FF) | uint16(val&0x07)<<8
+		p.length.load(val >> 3)
+		p.envelope.start = true
+		p.step = 0
+	}
+}
+
+//stepTimer ... Clocked every APU cycle (two CPU cycles)
+func (p *pulse) stepTimer() {
+	if p.timer == 0 {
+		p.timer = p.period
+		p.step = (p.step + 1) % 8
+	} else {
+		p.timer--
+	}
+}
+
+//sweepTarget ... Period the sweep unit is moving towards. Pulse 1 negates with
+//one's complement, pulse 2 with two's complement.
+func (p *pulse) sweepTarget() uint16 {
+	delta := p.period >> p.sweepShift
+	if !p.sweepNegate {
+		return p.period + delta
+	}
+	if p.channel == 1 {
+		delta++
+	}
+	if delta > p.period {
+		return 0
+	}
+	return p.period - delta
+}
+
+//clockSweep ... Half frame clock
+func (p *pulse) clockSweep() {
+	if p.sweepDivider == 0 && p.sweepEnabled && p.sweepShift > 0 && !p.muted() {
+		p.period = p.sweepTarget()
+	}
+	if p.sweepDivider == 0 || p.sweepReload {
+		p.sweepDivider = p.sweepPeriod
+		p.sweepReload = false
+	} else {
+		p.sweepDivider--
+	}
+}
+
+func (p *pulse) muted() bool {
+	return p.period < 8 || p.sweepTarget() > 0x7FF
+}
+
+func (p *pulse) output() byte {
+	if p.length.value == 0 || p.muted() || dutyTable[p.duty][p.step] == 0 {
+		return 0
+	}
+	return p.envelope.volume()
+}
+
+/*
+===============================================================================
+				Triangle
+===============================================================================
+*/
+
+//triangle ... Triangle wave channel ($4008-$400B)
+type triangle struct {
+	length        lengthCounter
+	linearPeriod  byte
+	linearCounter byte
+	linearReload  bool
+	step          byte
+	period        uint16
+	timer         uint16
+}
+
+func (t *triangle) write(reg uint16, val byte) {
+	switch reg {
+	case 0:
+		t.length.halt = hasBit(val, 7) // also the linear counter control flag
+		t.linearPeriod = val & 0x7F
+	case 2:
+		t.period = (t.period & 0x0700) | uint16(val)
+	case 3:
+		t.period = (t.period & 0x00FF) | uint16(val&0x07)<<8
+		t.length.load(val >> 3)
+		t.linearReload = true
+	}
+}
+
+//stepTimer ... Clocked every CPU cycle, the sequencer only advances while both
+//counters are non-zero
+func (t *triangle) stepTimer() {
+	if t.timer > 0 {
+		t.timer--
+		return
+	}
+	t.timer = t.period
+	if t.length.value > 0 && t.linearCounter > 0 {
+		t.step = (t.step + 1) % 32
+	}
+}
+
+//clockLinear ... Quarter frame clock
+func (t *triangle) clockLinear() {
+	if t.linearReload {
+		t.linearCounter = t.linearPeriod
+	} else if t.linearCounter > 0 {
+		t.linearCounter--
+	}
+	if !t.length.halt {
+		t.linearReload = false
+	}
+}
+
+func (t *triangle) output() byte {
+	// ultrasonic periods hold the output at the middle of the waveform instead
+	// of playing a tone nobody can hear, which avoids popping, like most emulators
+	if t.period < 2 {
+		return 7
+	}
+	return triangleTable[t.step]
+}
+
+/*
+===============================================================================
+				Noise
+===============================================================================
+*/
+
+//noise ... Pseudo-random noise channel ($400C-$400F)
+type noise struct {
+	length   lengthCounter
+	envelope envelope
+	mode     bool   //Short mode, feedback from bit 6 instead of bit 1
+	shift    uint16 //15 bit linear feedback shift register
+	period   uint16
+	timer    uint16
+}
+
+func (n *noise) write(reg uint16, val byte) {
+	switch reg {
+	case 0:
+		n.length.halt = hasBit(val, 5)
+		n.envelope.write(val)
+	case 2:
+		n.mode = hasBit(val, 7)
+		n.period = noiseTable[val&0x0F]
+	case 3:
+		n.length.load(val >> 3)
+		n.envelope.start = true
+	}
+}
+
+//stepTimer ... Clocked every CPU cycle
+func (n *noise) stepTimer() {
+	if n.timer > 0 {
+		n.timer--
+		return
+	}
+	n.timer = n.period
+	tap := uint(1)
+	if n.mode {
+		tap = 6
+	}
+	feedback := (n.shift & 1) ^ ((n.shift >> tap) & 1)
+	n.shift = (n.shift >> 1) | (feedback << 14)
+}
+
+func (n *noise) output() byte {
+	if n.length.value == 0 || n.shift&1 == 1 {
+		return 0
+	}
+	return n.envelope.volume()
+}
+
+/*
+===============================================================================
+				DMC
+===============================================================================
+*/
+
+//dmc ... Delta modulation channel ($4010-$4013). Plays 1-bit delta encoded
+//samples fetched from CPU memory, each fetch steals CPU cycles.
+type dmc struct {
+	bus Memory
+	cpu *CPU
+
+	irqEnabled bool
+	irq        bool
+	loop       bool
+	period     uint16
+	timer      uint16
+	level      byte
+
+	sampleAddress uint16
+	sampleLength  uint16
+	address       uint16 //Address of the next sample byte
+	remaining     uint16 //Sample bytes left to fetch
+
+	buffer     byte
+	bufferFull bool
+	shift      byte
+	bitsLeft   byte
+	silence    bool
+}
+
+func (d *dmc) write(reg uint16, val byte) {
+	switch reg {
+	case 0:
+		d.irqEnabled = hasBit(val, 7)
+		d.loop = hasBit(val, 6)
+		d.period = dmcTable[val&0x0F]
+		if !d.irqEnabled {
+			d.irq = false
+		}
+	case 1:
+		d.level = val & 0x7F
+	case 2:
+		d.sampleAddress = 0xC000 | uint16(val)<<6
+	case 3:
+		d.sampleLength = uint16(val)<<4 | 1
+	}
+}
+
+func (d *dmc) setEnabled(enabled bool) {
+	d.irq = false
+	if !enabled {
+		d.remaining = 0
+	} else if d.remaining == 0 {
+		d.restart()
+	}
+}
+
+func (d *dmc) restart() {
+	d.address = d.sampleAddress
+	d.remaining = d.sampleLength
+}
+
+//stepReader ... Memory reader, refills the sample buffer through DMA
+func (d *dmc) stepReader() {
+	if d.bufferFull || d.remaining == 0 {
+		return
+	}
+	d.cpu.stall(d.cpu.dmcStallCycles())
+	d.buffer = d.bus.read(d.address)
+	d.bufferFull = true
+	d.address++
+	if d.address == 0 {
+		d.address = 0x8000
+	}
+	d.remaining--
+	if d.remaining == 0 {
+		if d.loop {
+			d.restart()
+		} else if d.irqEnabled {
+			d.irq = true
+		}
+	}
+}
+
+//stepTimer ... Clocked every CPU cycle
+func (d *dmc) stepTimer() {
+	d.stepReader()
+	if d.timer > 0 {
+		d.timer--
+		return
+	}
+	d.timer = d.period - 1
+
+	if !d.silence {
+		if hasBit(d.shift, 0) {
+			if d.level <= 125 {
+				d.level += 2
+			}
+		} else if d.level >= 2 {
+			d.level -= 2
+		}
+	}
+	d.shift >>= 1
+	if d.bitsLeft > 0 {
+		d.bitsLeft--
+	}
+	if d.bitsLeft == 0 {
+		d.bitsLeft = 8
+		d.silence = !d.bufferFull
+		if d.bufferFull {
+			d.shift = d.buffer
+			d.bufferFull = false
+		}
+	}
+}
+
+func (d *dmc) output() byte {
+	return d.level
+}
+
+/*
+===============================================================================
+				Save State
+===============================================================================
+*/
+
+type lengthState struct {
+	Enabled, Halt bool
+	Value         byte
+}
+
+type envelopeState struct {
+	Start, Loop, Constant  bool
+	Period, Divider, Decay byte
+}
+
+type pulseState struct {
+	Length   lengthState
+	Envelope envelopeState
+	Duty     byte
+	Step     byte
+	Period   uint16
+	Timer    uint16
+
+	SweepEnabled bool
+	SweepPeriod  byte
+	SweepNegate  bool
+	SweepShift   byte
+	SweepDivider byte
+	SweepReload  bool
+}
+
+type triangleState struct {
+	Length        lengthState
+	LinearPeriod  byte
+	LinearCounter byte
+	LinearReload  bool
+	Step          byte
+	Period        uint16
+	Timer         uint16
+}
+
+type noiseState struct {
+	Length   lengthState
+	Envelope envelopeState
+	Mode     bool
+	Shift    uint16
+	Period   uint16
+	Timer    uint16
+}
+
+type dmcState struct {
+	IRQEnabled, IRQ, Loop bool
+	Period, Timer         uint16
+	Level                 byte
+
+	SampleAddress, SampleLength uint16
+	Address, Remaining          uint16
+
+	Buffer     byte
+	BufferFull bool
+	Shift      byte
+	BitsLeft   byte
+	Silence    bool
+}
+
+func (l *lengthCounter) state() lengthState {
+	return lengthState{Enabled: l.enabled, Halt: l.halt, Value: l.value}
+}
+
+func (l *lengthCounter) restore(s lengthState) {
+	l.enabled, l.halt, l.value = s.Enabled, s.Halt, s.Value
+}
+
+func (e *envelope) state() envelopeState {
+	return envelopeState{
+		Start:    e.start,
+		Loop:     e.loop,
+		Constant: e.constant,
+		Period:   e.period,
+		Divider:  e.divider,
+		Decay:    e.decay,
+	}
+}
+
+func (e *envelope) restore(s envelopeState) {
+	e.start, e.loop, e.constant = s.Start, s.Loop, s.Constant
+	e.period, e.divider, e.decay = s.Period, s.Divider, s.Decay
+}
+
+func (p *pulse) state() pulseState {
+	return pulseState{
+		Length:       p.length.state(),
+		Envelope:     p.envelope.state(),
+		Duty:         p.duty,
+		Step:         p.step,
+		Period:       p.period,
+		Timer:        p.timer,
+		SweepEnabled: p.sweepEnabled,
+		SweepPeriod:  p.sweepPeriod,
+		SweepNegate:  p.sweepNegate,
+		SweepShift:   p.sweepShift,
+		SweepDivider: p.sweepDivider,
+		SweepReload:  p.sweepReload,
+	}
+}
+
+func (p *pulse) restore(s pulseState) {
+	p.length.restore(s.Length)
+	p.envelope.restore(s.Envelope)
+	p.duty = s.Duty
+	p.step = s.Step
+	p.period = s.Period
+	p.timer = s.Timer
+	p.sweepEnabled = s.SweepEnabled
+	p.sweepPeriod = s.SweepPeriod
+	p.sweepNegate = s.SweepNegate
+	p.sweepShift = s.SweepShift
+	p.sweepDivider = s.SweepDivider
+	p.sweepReload = s.SweepReload
+}
+
+func (t *triangle) state() triangleState {
+	return triangleState{
+		Length:        t.length.state(),
+		LinearPeriod:  t.linearPeriod,
+		LinearCounter: t.linearCounter,
+		LinearReload:  t.linearReload,
+		Step:          t.step,
+		Period:        t.period,
+		Timer:         t.timer,
+	}
+}
+
+func (t *triangle) restore(s triangleState) {
+	t.length.restore(s.Length)
+	t.linearPeriod = s.LinearPeriod
+	t.linearCounter = s.LinearCounter
+	t.linearReload = s.LinearReload
+	t.step = s.Step
+	t.period = s.Period
+	t.timer = s.Timer
+}
+
+func (n *noise) state() noiseState {
+	return noiseState{
+		Length:   n.length.state(),
+		Envelope: n.envelope.state(),
+		Mode:     n.mode,
+		Shift:    n.shift,
+		Period:   n.period,
+		Timer:    n.timer,
+	}
+}
+
+func (n *noise) restore(s noiseState) {
+	n.length.restore(s.Length)
+	n.envelope.restore(s.Envelope)
+	n.mode = s.Mode
+	n.shift = s.Shift
+	n.period = s.Period
+	n.timer = s.Timer
+}
+
+func (d *dmc) state() dmcState {
+	return dmcState{
+		IRQEnabled:    d.irqEnabled,
+		IRQ:           d.irq,
+		Loop:          d.loop,
+		Period:        d.period,
+		Timer:         d.timer,
+		Level:         d.level,
+		SampleAddress: d.sampleAddress,
+		SampleLength:  d.sampleLength,
+		Address:       d.address,
+		Remaining:     d.remaining,
+		Buffer:        d.buffer,
+		BufferFull:    d.bufferFull,
+		Shift:         d.shift,
+		BitsLeft:      d.bitsLeft,
+		Silence:       d.silence,
+	}
+}
+
+func (d *dmc) restore(s dmcState) {
+	d.irqEnabled = s.IRQEnabled
+	d.irq = s.IRQ
+	d.loop = s.Loop
+	d.period = s.Period
+	d.timer = s.Timer
+	d.level = s.Level
+	d.sampleAddress = s.SampleAddress
+	d.sampleLength = s.SampleLength
+	d.address = s.Address
+	d.remaining = s.Remaining
+	d.buffer = s.Buffer
+	d.bufferFull = s.BufferFull
+	d.shift = s.Shift
+	d.bitsLeft = s.BitsLeft
+	d.silence = s.Silence
+}
@@ -0,0 +1,80 @@
+package nes
+
+import "io"
+
+//CNROM ... Mapper 3, fixed PRG ROM and a switchable 8KB CHR bank
+//Reference: https://wiki.nesdev.com/w/index.php/CNROM
+type CNROM struct {
+	board
+	chrBank byte
+}
+
+func newCNROM(rom *ROM) *CNROM {
+	m := &CNROM{board: newBoard(rom)}
+	m.busConflicts = hasBusConflicts(rom)
+	return m
+}
+
+func (m *CNROM) cpuRead(addr uint16) byte {
+	switch {
+	case addr >= 0x8000:
+		return m.prgROM[int(addr-0x8000)%len(m.prgROM)]
+	case addr >= 0x6000:
+		return m.readPRGRAM(addr)
+	}
+	return 0
+}
+
+func (m *CNROM) cpuWrite(addr uint16, val byte) {
+	switch {
+	case addr >= 0x8000:
+		m.chrBank = m.conflict(m, addr, val)
+	case addr >= 0x6000:
+		m.writePRGRAM(addr, val)
+	}
+}
+
+func (m *CNROM) chrAddress(addr uint16) int {
+	banks := len(m.chr) / chrUnitSize
+	if banks == 0 {
+		return int(addr) % len(m.chr)
+	}
+	return int(m.chrBank)%banks*chrUnitSize + int(addr&0x1FFF)
+}
+
+func (m *CNROM) ppuRead(addr uint16) byte {
+	return m.chr[m.chrAddress(addr)]
+}
+
+func (m *CNROM) ppuWrite(addr uint16, val byte) {
+	if m.chrRAM {
+		m.chr[m.chrAddress(addr)] = val
+	}
+}
+
+func (m *CNROM) mirroring() Mirroring {
+	return m.mirror
+}
+
+func (m *CNROM) irq() bool {
+	return false
+}
+
+type cnromState struct {
+	Board   boardState
+	CHRBank byte
+}
+
+func (m *CNROM) saveState(w io.Writer) error {
+	return encodeState(w, cnromState{Board: m.state(), CHRBank: m.chrBank})
+}
+
+func (m *CNROM) loadState(r io.Reader) error {
+	var s cnromState
+	if err := decodeState(r, &s); err != nil {
+		return err
+	}
+	m.restore(s.Board)
+	m.chrBank = s.CHRBank
+	return nil
+}
@@ -0,0 +1,75 @@
+package nes
+
+//Buttons ... Standard controller state, one bit per button in the order the
+//shift register reports them
+type Buttons byte
+
+//Standard controller buttons
+const (
+	ButtonA Buttons = 1 << iota
+	ButtonB
+	ButtonSelect
+	ButtonStart
+	ButtonUp
+	ButtonDown
+	ButtonLeft
+	ButtonRight
+)
+
+//InputSource ... Supplies controller state. Polled once per frame for each of
+//the two ports (0 and 1), so a frontend, replay file or test script can drive input.
+type InputSource interface {
+	Buttons(port int, frame uint64) Buttons
+}
+
+//StaticInput ... An InputSource reporting whatever state was last set, useful
+//for frontends that update it from keyboard or gamepad events
+type StaticInput [2]Buttons
+
+//Buttons ...
+func (s *StaticInput) Buttons(port int, frame uint64) Buttons {
+	return s[port]
+}
+
+//Controller ... Standard NES joypad, a parallel-in serial-out shift register
+//Reference: https://wiki.nesdev.com/w/index.php/Standard_controller
+type Controller struct {
+	buttons Buttons //State reported for the current frame
+	strobe  bool    //While set the register keeps reloading, reads return A
+	index   byte    //Next button to be shifted out
+}
+
+func (c *Controller) write(val byte) {
+	c.strobe = hasBit(val, 0)
+	if c.strobe {
+		c.index = 0
+	}
+}
+
+//read ... Returns the next button in bit 0. After all eight have been read the
+//register is empty and official controllers report 1.
+func (c *Controller) read() byte {
+	if c.strobe {
+		return byte(c.buttons & ButtonA)
+	}
+	if c.index > 7 {
+		return 1
+	}
+	bit := byte(c.buttons>>c.index) & 1
+	c.index++
+	return bit
+}
+
+type controllerState struct {
+	Buttons Buttons
+	Strobe  bool
+	Index   byte
+}
+
+func (c *Controller) state() controllerState {
+	return controllerState{Buttons: c.buttons, Strobe: c.strobe, Index: c.index}
+}
+
+func (c *Controller) restore(s controllerState) {
+	c.buttons, c.strobe, c.index = s.Buttons, s.Strobe, s.Index
+}
@@ -0,0 +1,31 @@
+package nes
+
+import "testing"
+
+//TestInputFirstFrame ... The input source is polled for frame 0 too, before
+//the PPU finishes its first frame
+func TestInputFirstFrame(t *testing.T) {
+	input := &StaticInput{ButtonA | ButtonStart, ButtonB}
+	console, err := New(nromImage([]byte{0xEA}, 0xC000), Options{Input: input})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := console.StepInstruction(); err != nil {
+		t.Fatal(err)
+	}
+	if console.PPU().Frame != 0 {
+		t.Fatalf("PPU is on frame %d", console.PPU().Frame)
+	}
+
+	console.bus.write(joypad1, 1)
+	console.bus.write(joypad1, 0)
+	for port, want := range input {
+		var got Buttons
+		for i := 0; i < 8; i++ {
+			got |= Buttons(console.bus.read(joypad1+uint16(port))&1) << i
+		}
+		if got != want {
+			t.Errorf("port %d reads %08b, want %08b", port, got, want)
+		}
+	}
+}
@@ -0,0 +1,1340 @@
+package nes
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+//CPU ... Represents a MOS 6502 CPU
+//Reference: http://www.obelisk.me.uk/6502/
+type CPU struct {
+	PC          uint16 //Program counter
+	A           byte   //Accumulator register
+	X           byte   //X register
+	Y           byte   //Y register
+	P           byte   //Status register
+	SP          byte   //Stack pointer
+	bus         Memory //CPU address bus
+	irqLines    byte   //Asserted IRQ sources, the IRQ input is their wired-OR
+	irqInhibit  bool   //I flag as seen by the last interrupt poll
+	nmiLine     bool   //Current level of the NMI input
+	nmiPending  bool   //Set on a rising edge of the NMI input
+	nmiPolled   bool   //nmiPending as seen by the last interrupt poll
+	irqPolled   bool   //IRQ input as seen by the last interrupt poll
+	Cycles      uint64 //CPU cycles since power on
+	pageCrossed bool   //Set by indexed addressing modes that cross a page
+	extraCycles int    //Cycles added by the current instruction, e.g. taken branches
+	stallCycles int    //Cycles the CPU is halted for by DMA
+	dmaAddr     uint16 //Next byte OAM DMA copies
+	dmaCycles   int    //Cycles of OAM DMA transfer left
+	dmaLatch    byte   //Byte read by OAM DMA, written to OAM on the next cycle
+	accesses    int    //Bus accesses made so far by the current Step
+	writing     bool   //The last bus access was a write
+	halted      bool   //Set by a JAM opcode, only RESET recovers
+	Magic       byte   //Chip dependent constant ORed into A by the unstable XAA and LXA
+	trace       func() //Called before each instruction when tracing
+}
+
+//UnknownOpcodeError ... Returned by Step for an opcode with no implementation.
+//PC is left pointing at the opcode.
+type UnknownOpcodeError struct {
+	PC     uint16
+	Opcode byte
+}
+
+func (e *UnknownOpcodeError) Error() string {
+	return fmt.Sprintf("cpu: unknown opcode $%02X at $%04X", e.Opcode, e.PC)
+}
+
+//IRQ sources
+const (
+	irqMapper byte = 1 << iota
+	irqFrameCounter
+	irqDMC
+)
+
+//Interrupt vectors
+const (
+	nmiVector   uint16 = 0xFFFA
+	resetVector uint16 = 0xFFFC
+	irqVector   uint16 = 0xFFFE
+)
+
+/*
+===============================================================================
+				CPU Initialization
+===============================================================================
+*/
+
+//init ... Power-on state. SP starts at $00 and ends up at $FD once the RESET
+//sequence has run, which also sets I.
+func (cpu *CPU) init(bus Memory) {
+	cpu.PC = 0x0000
+	cpu.A = 0
+	cpu.X = 0
+	cpu.Y = 0
+	cpu.SP = 0x00
+	cpu.Cycles = 0
+	cpu.bus = bus
+	cpu.P = 0x20
+	cpu.irqLines = 0
+	cpu.nmiLine = false
+	cpu.Magic = 0xEE
+	cpu.reset()
+}
+
+/*
+===============================================================================
+				Save State
+===============================================================================
+*/
+
+//cpuState ... Serializable CPU state. States are only taken between
+//instructions, so the scratch fields of an instruction in progress are left out.
+type cpuState struct {
+	PC             uint16
+	A, X, Y, P, SP byte
+	Cycles         uint64
+	IRQLines       byte
+	IRQInhibit     bool
+	NMILine        bool
+	NMIPending     bool
+	NMIPolled      bool
+	IRQPolled      bool
+	StallCycles    int
+	DMAAddr        uint16
+	DMACycles      int
+	DMALatch       byte
+	Writing        bool
+	Halted         bool
+	Magic          byte
+}
+
+func (cpu *CPU) state() cpuState {
+	return cpuState{
+		PC:          cpu.PC,
+		A:           cpu.A,
+		X:           cpu.X,
+		Y:           cpu.Y,
+		P:           cpu.P,
+		SP:          cpu.SP,
+		Cycles:      cpu.Cycles,
+		IRQLines:    cpu.irqLines,
+		IRQInhibit:  cpu.irqInhibit,
+		NMILine:     cpu.nmiLine,
+		NMIPending:  cpu.nmiPending,
+		NMIPolled:   cpu.nmiPolled,
+		IRQPolled:   cpu.irqPolled,
+		StallCycles: cpu.stallCycles,
+		DMAAddr:     cpu.dmaAddr,
+		DMACycles:   cpu.dmaCycles,
+		DMALatch:    cpu.dmaLatch,
+		Writing:     cpu.writing,
+		Halted:      cpu.halted,
+		Magic:       cpu.Magic,
+	}
+}
+
+func (cpu *CPU) restore(s cpuState) {
+	cpu.PC = s.PC
+	cpu.A, cpu.X, cpu.Y, cpu.P, cpu.SP = s.A, s.X, s.Y, s.P, s.SP
+	cpu.Cycles = s.Cycles
+	cpu.irqLines = s.IRQLines
+	cpu.irqInhibit = s.IRQInhibit
+	cpu.nmiLine = s.NMILine
+	cpu.nmiPending = s.NMIPending
+	cpu.nmiPolled = s.NMIPolled
+	cpu.irqPolled = s.IRQPolled
+	cpu.stallCycles = s.StallCycles
+	cpu.dmaAddr = s.DMAAddr
+	cpu.dmaCycles = s.DMACycles
+	cpu.dmaLatch = s.DMALatch
+	cpu.writing = s.Writing
+	cpu.halted = s.Halted
+	cpu.Magic = s.Magic
+}
+
+/*
+===============================================================================
+				Bus Access
+===============================================================================
+*/
+
+func (cpu *CPU) read(addr uint16) byte {
+	cpu.accesses++
+	cpu.writing = false
+	return cpu.bus.read(addr)
+}
+
+func (cpu *CPU) write(addr uint16, val byte) {
+	cpu.accesses++
+	cpu.writing = true
+	cpu.bus.write(addr, val)
+}
+
+//accessCycle ... The cycle the bus access in progress happens on. Cycles only
+//counts the instructions already finished, each access takes one more cycle.
+func (cpu *CPU) accessCycle() uint64 {
+	return cpu.Cycles + uint64(cpu.accesses) - 1
+}
+
+/*
+===============================================================================
+				Step and process and instruction
+===============================================================================
+*/
+
+//Step ... Executes one instruction and returns the number of CPU cycles it took.
+//While the CPU is stalled by DMA, or halted by a JAM opcode, each Step burns a
+//single cycle instead, making OAM DMA's access for that cycle if it's running. Interrupts are taken when the last poll saw them.
+func (cpu *CPU) Step() (int, error) {
+	cpu.accesses = 0
+	switch {
+	case cpu.stallCycles > 0:
+		cpu.stallCycles--
+		cpu.Cycles++
+		return 1, nil
+	case cpu.dmaCycles > 0:
+		cpu.oamDMACycle()
+		cpu.Cycles++
+		return 1, nil
+	case cpu.halted:
+		cpu.Cycles++
+		return 1, nil
+	case cpu.nmiPolled:
+		cpu.nmiPending = false
+		cpu.nmiPolled = false
+		cpu.interrupt(nmiVector)
+		cpu.Cycles += 7
+		return 7, nil
+	case cpu.irqPolled && !cpu.irqInhibit:
+		cpu.interrupt(irqVector)
+		cpu.Cycles += 7
+		return 7, nil
+	}
+	opcode := cpu.read(cpu.PC)
+	instruction := &instructions[opcode]
+	if instruction.execute == nil {
+		return 0, &UnknownOpcodeError{PC: cpu.PC, Opcode: opcode}
+	}
+	cycles := cpu.executeInstruction(instruction)
+	cpu.Cycles += uint64(cycles)
+	return cycles, nil
+}
+
+//Halted ... Reports whether a JAM opcode has locked up the CPU
+func (cpu *CPU) Halted() bool {
+	return cpu.halted
+}
+
+//executeInstruction ... Executes a CPU instructon and returns the number of
+//cycles it took, including page crossing and branch penalties
+func (cpu *CPU) executeInstruction(i *Instruction) int {
+	if cpu.trace != nil {
+		cpu.trace()
+	}
+	cpu.PC += i.size
+	cpu.pageCrossed = false
+	cpu.extraCycles = 0
+	p := cpu.P
+	i.execute(cpu, cpu.resolveOperand(i))
+	cpu.pollIRQ(i, p)
+
+	cycles := i.numCycles + cpu.extraCycles
+	if i.pageCycle && cpu.pageCrossed {
+		cycles++
+	}
+	return cycles
+}
+
+//stall ... Halts the CPU for a number of cycles while DMA owns the bus
+func (cpu *CPU) stall(cycles int) {
+	cpu.stallCycles += cycles
+}
+
+//dmcStallCycles ... CPU cycles a DMC sample fetch steals, which depends on the
+//cycle it lands on. The CPU can only be halted on a read, a fetch landing on a
+//write waits for it and steals one cycle less, and one during OAM DMA pauses
+//the transfer for two. The instruction-stepped core only sees the last access
+//of an instruction, so its counts are approximate and DMC DMA timing tests
+//don't pass. Double writes are taken as single ones by both cores.
+//Reference: https://wiki.nesdev.com/w/index.php/APU_DMC#Memory_reader
+func (cpu *CPU) dmcStallCycles() int {
+	switch {
+	case cpu.stallCycles > 0, cpu.dmaCycles > 0:
+		return 2
+	case cpu.writing:
+		return 3
+	}
+	return 4
+}
+
+//startOAMDMA ... Hands the bus to OAM DMA once the stall cycles are over, to
+//copy page $XX00-$XXFF to $2004
+func (cpu *CPU) startOAMDMA(page byte) {
+	cpu.dmaAddr = uint16(page) << 8
+	cpu.dmaCycles = 512
+}
+
+//oamDMACycle ... One cycle of OAM DMA, which alternates between reading the
+//next byte of the page and writing it to $2004
+func (cpu *CPU) oamDMACycle() {
+	if cpu.dmaCycles%2 == 0 {
+		cpu.dmaLatch = cpu.read(cpu.dmaAddr)
+		cpu.dmaAddr++
+	} else {
+		cpu.write(0x2004, cpu.dmaLatch)
+	}
+	cpu.dmaCycles--
+}
+
+/*
+===============================================================================
+				Interrupts
+===============================================================================
+*/
+
+//Reference: https://wiki.nesdev.com/w/index.php/CPU_interrupts
+
+//setIRQ ... Asserts or releases the IRQ line for an interrupt source.
+//IRQ is level triggered, it fires for as long as a source holds it and I is clear.
+func (cpu *CPU) setIRQ(source byte, asserted bool) {
+	if asserted {
+		cpu.irqLines |= source
+	} else {
+		cpu.irqLines &^= source
+	}
+}
+
+//setNMI ... Drives the NMI input. NMI is edge triggered, only a transition
+//from released to asserted requests an interrupt.
+func (cpu *CPU) setNMI(asserted bool) {
+	if asserted && !cpu.nmiLine {
+		cpu.nmiPending = true
+	}
+	cpu.nmiLine = asserted
+}
+
+//pollInterrupts ... Samples the NMI and IRQ inputs for the next Step to act on.
+//The CPU polls during the second to last cycle of each instruction, so the
+//console calls this before clocking the last cycle, and an interrupt raised on
+//that cycle is only taken after the following instruction.
+func (cpu *CPU) pollInterrupts() {
+	cpu.nmiPolled = cpu.nmiPending
+	cpu.irqPolled = cpu.irqLines != 0
+}
+
+//pollIRQ ... Latches the I flag used to decide whether an IRQ is taken before
+//the next instruction. CLI, SEI and PLP change I after the poll, so the
+//interrupt sees the flag from before they ran.
+func (cpu *CPU) pollIRQ(i *Instruction, p byte) {
+	if i.delaysIRQ {
+		cpu.irqInhibit = hasBit(p, 2)
+	} else {
+		cpu.irqInhibit = hasBit(cpu.P, 2)
+	}
+}
+
+//interrupt ... Hardware interrupt sequence for NMI and IRQ, takes 7 cycles.
+//Pushes PC and P with the B flag clear, then jumps through the interrupt vector.
+func (cpu *CPU) interrupt(vector uint16) {
+	// the opcode fetch is discarded and PC is not incremented
+	cpu.read(cpu.PC)
+	cpu.read(cpu.PC)
+	cpu.pushInterruptFrame(cpu.PC, clearBit(cpu.P, 4))
+	cpu.PC = cpu.interruptVector(vector)
+}
+
+//pushInterruptFrame ... Pushes the return address and status, then sets I
+func (cpu *CPU) pushInterruptFrame(pc uint16, p byte) {
+	bytes := make([]byte, 2)
+	binary.BigEndian.PutUint16(bytes, pc)
+	cpu.sPush(bytes...)
+	cpu.sPush(setBit(p, 5))
+	cpu.SEI()
+	cpu.irqInhibit = true
+}
+
+//interruptVector ... Reads the vector an interrupt sequence jumps through. An NMI
+//that arrives before the vector fetch hijacks an IRQ or BRK, which then jumps
+//through the NMI vector instead.
+func (cpu *CPU) interruptVector(vector uint16) uint16 {
+	if vector == irqVector && cpu.nmiPending {
+		cpu.nmiPending = false
+		cpu.nmiPolled = false
+		vector = nmiVector
+	}
+	return binary.LittleEndian.Uint16([]byte{cpu.read(vector), cpu.read(vector + 1)})
+}
+
+//reset ... RESET sequence. Runs the interrupt sequence with writes suppressed,
+//so SP is decremented by 3 without touching the stack, then jumps through $FFFC.
+func (cpu *CPU) reset() {
+	cpu.read(cpu.PC)
+	cpu.read(cpu.PC)
+	for i := 0; i < 3; i++ {
+		cpu.read(0x0100 | uint16(cpu.SP))
+		cpu.SP--
+	}
+	cpu.SEI()
+	cpu.irqInhibit = true
+	cpu.nmiPending = false
+	cpu.nmiPolled = false
+	cpu.irqPolled = false
+	cpu.halted = false
+	cpu.PC = cpu.interruptVector(resetVector)
+	cpu.Cycles += 7
+}
+
+/*
+===============================================================================
+				Addressing Modes
+===============================================================================
+*/
+
+//operand ... What an instruction works on, resolved from its addressing mode
+type operand struct {
+	kind operandKind
+	addr uint16 //Effective address of a memory operand, or of an immediate byte
+}
+
+//operandKind ... Where an operand's value lives
+type operandKind byte
+
+const (
+	operandNone        operandKind = iota //Implied, the instruction names its registers
+	operandAccumulator                    //The A register
+	operandImmediate                      //The byte following the opcode
+	operandMemory                         //Memory at an effective address
+)
+
+//resolveOperand ... Resolves the operand of an instruction once PC has moved
+//past it. Branches read their offset as an immediate.
+func (cpu *CPU) resolveOperand(i *Instruction) operand {
+	switch i.mode {
+	case implied:
+		return operand{kind: operandNone}
+	case accumulator:
+		return operand{kind: operandAccumulator}
+	case immediate, relative:
+		return operand{kind: operandImmediate, addr: cpu.immediateAddress()}
+	}
+	return operand{kind: operandMemory, addr: cpu.operandAddress(i)}
+}
+
+//operandAddress ... Effective address of a memory operand
+func (cpu *CPU) operandAddress(i *Instruction) uint16 {
+	switch i.mode {
+	case zeroPage:
+		return cpu.zeroPageAddress()
+	case zeroPageX:
+		return cpu.zeroPageXAddress()
+	case zeroPageY:
+		return cpu.zeroPageYAddress()
+	case absolute:
+		return cpu.absoluteAddress()
+	case absoluteX:
+		return cpu.indexedDummyRead(cpu.absoluteXAddress(), i)
+	case absoluteY:
+		return cpu.indexedDummyRead(cpu.absoluteYAddress(), i)
+	case indirect:
+		return cpu.indirectAddress()
+	case indexedIndirect:
+		return cpu.indexedIndirectAddress()
+	case indirectIndexed:
+		return cpu.indexedDummyRead(cpu.indirectIndexedAddress(), i)
+	}
+	return 0
+}
+
+//indexedDummyRead ... Indexed modes add the index to the low byte and read
+//before the carry reaches the high byte. Reads only spend that cycle when the
+//page was crossed and the value is wrong, writes and read-modify-writes always
+//do, which is why they have no page crossing penalty.
+func (cpu *CPU) indexedDummyRead(addr uint16, i *Instruction) uint16 {
+	if cpu.pageCrossed {
+		cpu.read(addr - 0x100)
+	} else if !i.pageCycle {
+		cpu.read(addr)
+	}
+	return addr
+}
+
+func (cpu *CPU) immediateAddress() uint16 {
+	return cpu.PC - 1
+}
+
+//Zero page indexing and pointers never leave page zero, the carry out of the
+//low byte is dropped. Absolute indexing wraps around the 16 bit address space.
+//Reference: https://wiki.nesdev.com/w/index.php/CPU_addressing_modes
+
+func (cpu *CPU) zeroPageAddress() uint16 {
+	return uint16(cpu.read(cpu.immediateAddress()))
+}
+
+func (cpu *CPU) zeroPageXAddress() uint16 {
+	base := cpu.read(cpu.immediateAddress())
+	cpu.read(uint16(base)) // dummy read while X is added
+	return uint16(base + cpu.X)
+}
+
+func (cpu *CPU) zeroPageYAddress() uint16 {
+	base := cpu.read(cpu.immediateAddress())
+	cpu.read(uint16(base)) // dummy read while Y is added
+	return uint16(base + cpu.Y)
+}
+
+func (cpu *CPU) absoluteAddress() uint16 {
+	lo := cpu.read(cpu.PC - 2)
+	hi := cpu.read(cpu.PC - 1)
+	return uint16(hi)<<8 | uint16(lo)
+}
+
+func (cpu *CPU) absoluteXAddress() uint16 {
+	base := cpu.absoluteAddress()
+	addr := base + uint16(cpu.X)
+	cpu.pageCrossed = pagesDiffer(base, addr)
+	return addr
+}
+
+func (cpu *CPU) absoluteYAddress() uint16 {
+	base := cpu.absoluteAddress()
+	addr := base + uint16(cpu.Y)
+	cpu.pageCrossed = pagesDiffer(base, addr)
+	return addr
+}
+
+//indirectAddress ... JMP ($xxxx). The pointer's high byte is fetched without
+//carrying into the page, so JMP ($02FF) reads $02FF and $0200.
+func (cpu *CPU) indirectAddress() uint16 {
+	ptr := cpu.absoluteAddress()
+	lo := cpu.read(ptr)
+	hi := cpu.read(ptr&0xFF00 | uint16(byte(ptr)+1))
+	return uint16(hi)<<8 | uint16(lo)
+}
+
+func (cpu *CPU) indexedIndirectAddress() uint16 {
+	base := cpu.read(cpu.immediateAddress())
+	cpu.read(uint16(base)) // dummy read while X is added
+	return cpu.zeroPagePointer(base + cpu.X)
+}
+
+func (cpu *CPU) indirectIndexedAddress() uint16 {
+	base := cpu.zeroPagePointer(cpu.read(cpu.immediateAddress()))
+	addr := base + uint16(cpu.Y)
+	cpu.pageCrossed = pagesDiffer(base, addr)
+	return addr
+}
+
+//zeroPagePointer ... Reads a little endian pointer from page zero, a pointer
+//at $FF takes its high byte from $00
+func (cpu *CPU) zeroPagePointer(ptr byte) uint16 {
+	lo := cpu.read(uint16(ptr))
+	hi := cpu.read(uint16(ptr + 1))
+	return uint16(hi)<<8 | uint16(lo)
+}
+
+//pagesDiffer ... Reports whether two addresses are on different 256 byte pages
+func pagesDiffer(a, b uint16) bool {
+	return a&0xFF00 != b&0xFF00
+}
+
+//branch ... Relative branch. The signed offset is always fetched, a taken branch
+//costs one extra cycle and another if the target is on a different page.
+func (cpu *CPU) branch(addr uint16, taken bool) {
+	offset := uint16(int8(cpu.read(addr)))
+	if !taken {
+		return
+	}
+	target := cpu.PC + offset
+	cpu.extraCycles++
+	if pagesDiffer(cpu.PC, target) {
+		cpu.extraCycles++
+	}
+	cpu.PC = target
+}
+
+/*
+===============================================================================
+				Stack Operators
+===============================================================================
+*/
+
+func (cpu *CPU) sPush(bytes ...byte) {
+	for _, b := range bytes {
+		addr := binary.LittleEndian.Uint16([]byte{cpu.SP, 0x01}) // stack
+		cpu.write(addr, b)
+		cpu.SP--
+	}
+}
+
+func (cpu *CPU) sPop() byte {
+	cpu.SP++
+	addr := binary.LittleEndian.Uint16([]byte{cpu.SP, 0x01}) //stack
+	return cpu.read(addr)
+}
+
+/*
+===============================================================================
+				Flag Setters (WIP)
+===============================================================================
+*/
+
+func (cpu *CPU) checkAndSetZeroFlag(val byte) {
+	if val == 0 {
+		cpu.P = setBit(cpu.P, 1)
+	} else {
+		cpu.P = clearBit(cpu.P, 1)
+	}
+}
+
+func (cpu *CPU) checkAndSetNegativeFlag(val byte) {
+	if val&(1<<7) > 0 {
+		cpu.P = setBit(cpu.P, 7)
+	} else {
+		cpu.P = clearBit(cpu.P, 7)
+	}
+}
+
+func (cpu *CPU) checkAndSetOverflowFlag(val byte) {
+	if val&(1<<6) > 0 {
+		cpu.P = setBit(cpu.P, 6)
+	} else {
+		cpu.P = clearBit(cpu.P, 6)
+	}
+}
+
+//modify ... Read-modify-write. The 6502 writes the unmodified value back while
+//the ALU works, then writes the result, so registers see two writes.
+//Reference: https://wiki.nesdev.com/w/index.php/CPU_addressing_modes
+func (cpu *CPU) modify(addr uint16, op func(val byte) byte) byte {
+	val := cpu.read(addr)
+	cpu.write(addr, val)
+	val = op(val)
+	cpu.write(addr, val)
+	return val
+}
+
+//modifyOperand ... Shifts and rotates work on the accumulator, or on memory
+//with a read-modify-write
+func (cpu *CPU) modifyOperand(op operand, f func(val byte) byte) {
+	if op.kind == operandAccumulator {
+		cpu.A = f(cpu.A)
+		return
+	}
+	cpu.modify(op.addr, f)
+}
+
+func increment(val byte) byte {
+	return val + 1
+}
+
+func decrement(val byte) byte {
+	return val - 1
+}
+
+/*
+===============================================================================
+				INSTRUCTIONS BEGIN
+===============================================================================
+*/
+
+//AAX ... AND X register with A and store result in memory <addr>
+//flags: N,Z
+func (cpu *CPU) AAX(addr uint16) {
+	val := cpu.A & cpu.X
+	cpu.write(addr, val)
+}
+
+//ADC ... Add with Carry
+//A,Z,C,N = A+M+C
+func (cpu *CPU) ADC(addr uint16) {
+	cpu.adc(cpu.read(addr))
+}
+
+func (cpu *CPU) adc(M byte) {
+	A := cpu.A
+	C := byte(0x00)
+	if hasBit(cpu.P, 0) == true {
+		C = 0x01
+	}
+
+	cpu.A += (M + C)
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+	if int(A)+int(M)+int(C) > 0xFF {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	if (A^M)&0x80 == 0 && (A^cpu.A)&0x80 != 0 {
+		cpu.P = setBit(cpu.P, 6)
+	} else {
+		cpu.P = clearBit(cpu.P, 6)
+	}
+}
+
+//ALR ... AND with the immediate operand then LSR the accumulator (UNOFFICIAL)
+func (cpu *CPU) ALR(addr uint16) {
+	cpu.A &= cpu.read(addr)
+	if hasBit(cpu.A, 0) {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	cpu.A >>= 1
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+}
+
+//ANC ... AND with the immediate operand, bit 7 of the result is also copied
+//into the carry as if it had been shifted out (UNOFFICIAL)
+func (cpu *CPU) ANC(addr uint16) {
+	cpu.AND(addr)
+	if hasBit(cpu.A, 7) {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+}
+
+//AND ... Logical AND performed between A register and contents of Memory (A&M)
+func (cpu *CPU) AND(addr uint16) {
+	cpu.and(cpu.read(addr))
+}
+
+func (cpu *CPU) and(M byte) {
+	cpu.A = (cpu.A & M)
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+}
+
+//ARR ... AND with the immediate operand then ROR the accumulator. The carry
+//comes from bit 6 of the result and overflow from bit 6 XOR bit 5 (UNOFFICIAL)
+func (cpu *CPU) ARR(addr uint16) {
+	val := cpu.A & cpu.read(addr)
+	cpu.A = val >> 1
+	if hasBit(cpu.P, 0) {
+		cpu.A = setBit(cpu.A, 7)
+	}
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+	if hasBit(cpu.A, 6) {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	if hasBit(cpu.A, 6) != hasBit(cpu.A, 5) {
+		cpu.P = setBit(cpu.P, 6)
+	} else {
+		cpu.P = clearBit(cpu.P, 6)
+	}
+}
+
+//ASL ... Arithmetic Shift Left
+//A,Z,C,N = M*2 or M,Z,C,N = M*2
+//This operation shifts all the bits of the accumulator or memory contents one bit left.
+//Bit 0 is set to 0 and bit 7 is placed in the carry flag
+//The effect of this operation is to multiply the memory contents by 2
+//(ignoring 2's complement considerations), setting the carry if the result will not fit in 8 bits.
+func (cpu *CPU) ASL(op operand) {
+	cpu.modifyOperand(op, cpu.asl)
+}
+
+func (cpu *CPU) asl(oval byte) byte {
+	nval := oval << 1
+	if hasBit(oval, 7) {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	cpu.checkAndSetZeroFlag(nval)
+	cpu.checkAndSetNegativeFlag(nval)
+	return nval
+}
+
+//ASO ... This opcode ASLs the contents of a memory location and then ORs
+//the result with the accumulator.
+func (cpu *CPU) ASO(addr uint16) {
+	cpu.ora(cpu.modify(addr, cpu.asl))
+}
+
+//AXS ... Also known as SBX. X = (A & X) - M, setting flags like CMP without
+//borrowing (UNOFFICIAL)
+func (cpu *CPU) AXS(addr uint16) {
+	M := cpu.read(addr)
+	val := cpu.A & cpu.X
+	if val >= M {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	cpu.X = val - M
+	cpu.checkAndSetZeroFlag(cpu.X)
+	cpu.checkAndSetNegativeFlag(cpu.X)
+}
+
+//BCC ... Branch if carry clear (If CPU.P.carry = false)
+func (cpu *CPU) BCC(addr uint16) {
+	cpu.branch(addr, hasBit(cpu.P, 0) == false)
+}
+
+//BCS ... Branch if carry set (If CPU.P.carry = true)
+func (cpu *CPU) BCS(addr uint16) {
+	cpu.branch(addr, hasBit(cpu.P, 0) == true)
+}
+
+//BEQ ... Branch if equal (If CPU.P.zero = true)
+func (cpu *CPU) BEQ(addr uint16) {
+	cpu.branch(addr, hasBit(cpu.P, 1) == true)
+}
+
+//BIT ... Bit Test
+func (cpu *CPU) BIT(addr uint16) {
+	val := cpu.read(addr)
+	if (cpu.A & val) == 0 {
+		cpu.P = setBit(cpu.P, 1)
+	} else {
+		cpu.P = clearBit(cpu.P, 1)
+	}
+	cpu.checkAndSetOverflowFlag(val)
+	cpu.checkAndSetNegativeFlag(val)
+}
+
+//BMI ... Branch if minus
+func (cpu *CPU) BMI(addr uint16) {
+	cpu.branch(addr, hasBit(cpu.P, 7))
+}
+
+//BNE ... Branch if not equal (If CPU.P.zero = false)
+func (cpu *CPU) BNE(addr uint16) {
+	cpu.branch(addr, hasBit(cpu.P, 1) == false)
+}
+
+//BPL ... Branch if positive (If CPU.P.NegativeFlag = false, advance program counter)
+func (cpu *CPU) BPL(addr uint16) {
+	cpu.branch(addr, hasBit(cpu.P, 7) == false)
+}
+
+//BRK ... Force Interrupt
+//The BRK instruction forces the generation of an interrupt request.
+//The program counter and processor status are pushed on the stack then the IRQ
+//interrupt vector at $FFFE/F is loaded into the PC and the break flag in the status set to one.
+//BRK is two bytes long, the byte after the opcode is read and skipped.
+//The pushed status has the B flag set to tell it apart from a hardware IRQ.
+func (cpu *CPU) BRK() {
+	cpu.read(cpu.PC)
+	cpu.pushInterruptFrame(cpu.PC+1, setBit(cpu.P, 4))
+	cpu.PC = cpu.interruptVector(irqVector)
+}
+
+//BVC ... Branch if Overflow Clear
+func (cpu *CPU) BVC(addr uint16) {
+	cpu.branch(addr, hasBit(cpu.P, 6) == false)
+}
+
+//BVS ... Branch if Overflow Set
+func (cpu *CPU) BVS(addr uint16) {
+	cpu.branch(addr, hasBit(cpu.P, 6) == true)
+}
+
+//CLC ... Clears Carry Flag
+func (cpu *CPU) CLC() {
+	cpu.P = clearBit(cpu.P, 0)
+}
+
+//CLD ... Clears DecimalMode flag
+func (cpu *CPU) CLD() {
+	cpu.P = clearBit(cpu.P, 3)
+}
+
+//CLI ... Clears InterruptDisable flag
+func (cpu *CPU) CLI() {
+	cpu.P = clearBit(cpu.P, 2)
+}
+
+//CLV ... Clears Overflow Flag
+func (cpu *CPU) CLV() {
+	cpu.P = clearBit(cpu.P, 6)
+}
+
+//CMP ...
+func (cpu *CPU) CMP(addr uint16) {
+	cpu.cmp(cpu.read(addr))
+}
+
+func (cpu *CPU) cmp(M byte) {
+	res := (cpu.A - M)
+	if cpu.A >= M {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	if cpu.A == M {
+		cpu.P = setBit(cpu.P, 1)
+	} else {
+		cpu.P = clearBit(cpu.P, 1)
+	}
+	cpu.checkAndSetNegativeFlag(res)
+}
+
+//CPX ... Compare X register -- Z,C,N = X-M
+func (cpu *CPU) CPX(addr uint16) {
+	M := cpu.read(addr)
+	res := (cpu.X - M)
+	if cpu.X >= M {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	if cpu.X == M {
+		cpu.P = setBit(cpu.P, 1)
+	} else {
+		cpu.P = clearBit(cpu.P, 1)
+	}
+	cpu.checkAndSetNegativeFlag(res)
+}
+
+//CPY ... Compare Y register -- Z,C,N = Y-M
+func (cpu *CPU) CPY(addr uint16) {
+	M := cpu.read(addr)
+	res := (cpu.Y - M)
+	if cpu.Y >= M {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	if cpu.Y == M {
+		cpu.P = setBit(cpu.P, 1)
+	} else {
+		cpu.P = clearBit(cpu.P, 1)
+	}
+	cpu.checkAndSetNegativeFlag(res)
+}
+
+//DCP ... Subtract 1 from memory (without borrow).
+func (cpu *CPU) DCP(addr uint16) {
+	cpu.cmp(cpu.modify(addr, decrement))
+}
+
+//DEC ... Decrement memory -- M,Z,N = M-1
+func (cpu *CPU) DEC(addr uint16) {
+	nval := cpu.modify(addr, decrement)
+	cpu.checkAndSetZeroFlag(nval)
+	cpu.checkAndSetNegativeFlag(nval)
+}
+
+//DEX ... Decrement X Register -- X,Z,N = X-1
+func (cpu *CPU) DEX() {
+	cpu.X--
+	cpu.checkAndSetZeroFlag(cpu.X)
+	cpu.checkAndSetNegativeFlag(cpu.X)
+}
+
+//DEY ... Decrement Y Register -- Y,Z,N = Y-1
+func (cpu *CPU) DEY() {
+	cpu.Y--
+	cpu.checkAndSetZeroFlag(cpu.Y)
+	cpu.checkAndSetNegativeFlag(cpu.Y)
+}
+
+//EOR ... Exclusing OR is performed between A register and contents of Memory
+//A,Z,N = A^M
+func (cpu *CPU) EOR(addr uint16) {
+	cpu.eor(cpu.read(addr))
+}
+
+func (cpu *CPU) eor(M byte) {
+	cpu.A = (cpu.A ^ M)
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+}
+
+//IGN ... Unofficial NOP with an operand, reads it and ignores the value. The
+//read still has side effects, e.g. IGN $2002 clears the VBlank flag.
+func (cpu *CPU) IGN(addr uint16) {
+	cpu.read(addr)
+}
+
+//INC ... Increment memory -- M,Z,N = M+1
+func (cpu *CPU) INC(addr uint16) {
+	nval := cpu.modify(addr, increment)
+	cpu.checkAndSetZeroFlag(nval)
+	cpu.checkAndSetNegativeFlag(nval)
+}
+
+//INX ... Increment X Register -- X,Z,N = X+1
+func (cpu *CPU) INX() {
+	cpu.X++
+	cpu.checkAndSetZeroFlag(cpu.X)
+	cpu.checkAndSetNegativeFlag(cpu.X)
+}
+
+//INY ... Increment Y Register -- Y,Z,N = Y+1
+func (cpu *CPU) INY() {
+	cpu.Y++
+	cpu.checkAndSetZeroFlag(cpu.Y)
+	cpu.checkAndSetNegativeFlag(cpu.Y)
+}
+
+//ISC ... This opcode INCs the contents of a memory location and then SBCs
+//the result from the A register.
+func (cpu *CPU) ISC(addr uint16) {
+	cpu.sbc(cpu.modify(addr, increment))
+}
+
+//JMP ... Moves Program Counter to address, addr
+func (cpu *CPU) JMP(addr uint16) {
+	cpu.PC = addr
+}
+
+//JSR -- The JSR instruction pushes the address (minus one) of
+//the return point on to the stack and then sets
+//the program counter to the target memory address.
+func (cpu *CPU) JSR(addr uint16) {
+	bytes := make([]byte, 2)
+	binary.BigEndian.PutUint16(bytes, cpu.PC-1)
+	cpu.sPush(bytes...)
+	cpu.PC = addr
+}
+
+//KIL ... Also known as JAM. Locks up the CPU with PC left on the opcode, it
+//stops responding to interrupts until RESET.
+func (cpu *CPU) KIL() {
+	cpu.PC--
+	cpu.halted = true
+}
+
+//LAS ... AND memory with SP and load the result into A, X and SP (UNOFFICIAL)
+func (cpu *CPU) LAS(addr uint16) {
+	val := cpu.read(addr) & cpu.SP
+	cpu.A = val
+	cpu.X = val
+	cpu.SP = val
+	cpu.checkAndSetZeroFlag(val)
+	cpu.checkAndSetNegativeFlag(val)
+}
+
+//LAX ... Load accumulator and X register from memory address addr
+func (cpu *CPU) LAX(addr uint16) {
+	val := cpu.read(addr)
+	cpu.A = val
+	cpu.X = val
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+}
+
+//LDA ... Loads the byte at location, addr, into the A register
+func (cpu *CPU) LDA(addr uint16) {
+	val := cpu.read(addr)
+	cpu.A = val
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+}
+
+//LDX ... Loads the byte at location, addr, into the X register
+func (cpu *CPU) LDX(addr uint16) {
+	val := cpu.read(addr)
+	cpu.X = val
+	cpu.checkAndSetZeroFlag(val)
+	cpu.checkAndSetNegativeFlag(val)
+}
+
+//LDY ... Loads the byte at location, addr, into the Y register
+func (cpu *CPU) LDY(addr uint16) {
+	val := cpu.read(addr)
+	cpu.Y = val
+	cpu.checkAndSetZeroFlag(cpu.Y)
+	cpu.checkAndSetNegativeFlag(cpu.Y)
+}
+
+//LSE ... LSE LSRs the contents of a memory location and then EORs
+//the result with the accumulator.
+func (cpu *CPU) LSE(addr uint16) {
+	cpu.eor(cpu.modify(addr, cpu.lsr))
+}
+
+//LSR ... Logical shift right
+//A,C,Z,N = A/2 or M,C,Z,N = M/2
+//Each of the bits in A or M is shift one place to the right.
+//The bit that was in bit 0 is shifted into the carry flag. Bit 7 is set to zero
+func (cpu *CPU) LSR(op operand) {
+	cpu.modifyOperand(op, cpu.lsr)
+}
+
+func (cpu *CPU) lsr(oval byte) byte {
+	nval := oval >> 1
+	if hasBit(oval, 0) {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	cpu.checkAndSetZeroFlag(nval)
+	cpu.checkAndSetNegativeFlag(nval)
+	return nval
+}
+
+//LXA ... Load A and X with (A | Magic) & M. Unstable, the constant depends on
+//the chip and temperature (UNOFFICIAL)
+func (cpu *CPU) LXA(addr uint16) {
+	val := (cpu.A | cpu.Magic) & cpu.read(addr)
+	cpu.A = val
+	cpu.X = val
+	cpu.checkAndSetZeroFlag(val)
+	cpu.checkAndSetNegativeFlag(val)
+}
+
+//NOP ... No Operation
+func (cpu *CPU) NOP() {
+}
+
+//ORA ... Inclusive OR is performed between A register and contents of Memory (A|M)
+func (cpu *CPU) ORA(addr uint16) {
+	cpu.ora(cpu.read(addr))
+}
+
+func (cpu *CPU) ora(M byte) {
+	cpu.A = (cpu.A | M)
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+}
+
+//PHA ... Push Accumulator to Stack
+func (cpu *CPU) PHA() {
+	cpu.sPush(cpu.A)
+}
+
+//PHP ... Push Processor Status to Stack
+func (cpu *CPU) PHP() {
+	b := cpu.P
+	b = setBit(b, 5)
+	b = setBit(b, 4)
+	cpu.sPush(b)
+}
+
+//PLA ... Pull an 8 bit value from stack and stores in the Accumulator (setting zero and negative)
+func (cpu *CPU) PLA() {
+	cpu.A = cpu.sPop()
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+}
+
+//PLP ... Pull an 8 bit value from stack and into the processor status register
+func (cpu *CPU) PLP() {
+	val := cpu.sPop()
+	val = setBit(val, 5)
+	val = clearBit(val, 4)
+	cpu.P = val
+}
+
+//RLA ...ROLs the contents of a memory location and then ANDs the result with
+//the accumulator.
+func (cpu *CPU) RLA(addr uint16) {
+	cpu.and(cpu.modify(addr, cpu.rol))
+}
+
+//ROL ... Rotate Left
+//Move each of the bits in either A or M one place to the left.
+//Bit 0 is filled with the current value of the carry flag whilst
+//the old bit 7 becomes the new carry flag value.
+func (cpu *CPU) ROL(op operand) {
+	cpu.modifyOperand(op, cpu.rol)
+}
+
+func (cpu *CPU) rol(oval byte) byte {
+	nval := oval<<1 | cpu.P&0x01
+	if hasBit(oval, 7) {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	cpu.checkAndSetNegativeFlag(nval)
+	cpu.checkAndSetZeroFlag(nval)
+	return nval
+}
+
+//ROR ... Rotate Right
+//Move each of the bits in either A or M one place to the right.
+//Bit 7 is filled with the current value of the carry flag whilst
+//the old bit 0 becomes the new carry flag value.
+func (cpu *CPU) ROR(op operand) {
+	cpu.modifyOperand(op, cpu.ror)
+}
+
+func (cpu *CPU) ror(oval byte) byte {
+	nval := oval>>1 | cpu.P<<7
+	if hasBit(oval, 0) {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	cpu.checkAndSetNegativeFlag(nval)
+	cpu.checkAndSetZeroFlag(nval)
+	return nval
+}
+
+//RRA ... RORs the contents of a memory location and then ADCs the result with
+//the accumulator.
+func (cpu *CPU) RRA(addr uint16) {
+	cpu.adc(cpu.modify(addr, cpu.ror))
+}
+
+//RTI ... Return from Interrupt
+//The RTI instruction is used at the end of an interrupt processing routine.
+//It pulls the processor flags from the stack followed by the program counter.
+func (cpu *CPU) RTI() {
+	cpu.PLP() //Pull processor status from stack
+	lo := cpu.sPop()
+	hi := cpu.sPop()
+	cpu.PC = binary.LittleEndian.Uint16([]byte{lo, hi})
+}
+
+//RTS ...
+func (cpu *CPU) RTS() {
+	lo := cpu.sPop()
+	hi := cpu.sPop()
+	addr := binary.LittleEndian.Uint16([]byte{lo, hi})
+	cpu.PC = addr + 1
+}
+
+//SBC ... Subtract with Carry
+//Subtracts the contents of a memory location to the accumulator together with the
+//not of the carry bit. If overflow occurs the carry bit is clear
+//A,Z,C,N = A-M-(1-C)
+func (cpu *CPU) SBC(addr uint16) {
+	cpu.sbc(cpu.read(addr))
+}
+
+func (cpu *CPU) sbc(M byte) {
+	A := cpu.A
+	C := byte(0x00)
+	if hasBit(cpu.P, 0) == true {
+		C = 0x01
+	}
+	cpu.A = A - M - (1 - C)
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+	if int(A)-int(M)-int(1-C) >= 0 {
+		cpu.P = setBit(cpu.P, 0)
+	} else {
+		cpu.P = clearBit(cpu.P, 0)
+	}
+	if (A^M)&0x80 != 0 && (A^cpu.A)&0x80 != 0 {
+		cpu.P = setBit(cpu.P, 6)
+	} else {
+		cpu.P = clearBit(cpu.P, 6)
+	}
+}
+
+//SEC ... Set Carry Flag
+func (cpu *CPU) SEC() {
+	cpu.P = setBit(cpu.P, 0)
+}
+
+//SED ... Sets decimal flag
+func (cpu *CPU) SED() {
+	cpu.P = setBit(cpu.P, 3)
+}
+
+//SEI ... Sets InterruptDisable flag on CPU status register
+func (cpu *CPU) SEI() {
+	cpu.P = setBit(cpu.P, 2)
+}
+
+//SHA ... Also known as AHX. Stores A & X & (high byte of the base address + 1)
+//(UNOFFICIAL)
+func (cpu *CPU) SHA(addr uint16) {
+	cpu.unstableStore(addr, cpu.Y, cpu.A&cpu.X)
+}
+
+//SHX ... Stores X & (high byte of the base address + 1) (UNOFFICIAL)
+func (cpu *CPU) SHX(addr uint16) {
+	cpu.unstableStore(addr, cpu.Y, cpu.X)
+}
+
+//SHY ... Stores Y & (high byte of the base address + 1) (UNOFFICIAL)
+func (cpu *CPU) SHY(addr uint16) {
+	cpu.unstableStore(addr, cpu.X, cpu.Y)
+}
+
+//unstableStore ... The SH* stores AND their value with the high byte of the
+//base address plus one. When indexing crosses a page the high byte of the
+//target address is replaced by the stored value as well.
+func (cpu *CPU) unstableStore(addr uint16, index byte, val byte) {
+	base := addr - uint16(index)
+	val &= byte(base>>8) + 1
+	if pagesDiffer(base, addr) {
+		addr = uint16(val)<<8 | addr&0x00FF
+	}
+	cpu.write(addr, val)
+}
+
+//STA ... M = A
+func (cpu *CPU) STA(addr uint16) {
+	cpu.write(addr, cpu.A)
+}
+
+//STX ... M = X
+func (cpu *CPU) STX(addr uint16) {
+	cpu.write(addr, cpu.X)
+}
+
+//STY ... M = Y
+func (cpu *CPU) STY(addr uint16) {
+	cpu.write(addr, cpu.Y)
+}
+
+//TAS ... Also known as SHS. Sets SP to A & X, then stores SP & (high byte of
+//the base address + 1) (UNOFFICIAL)
+func (cpu *CPU) TAS(addr uint16) {
+	cpu.SP = cpu.A & cpu.X
+	cpu.unstableStore(addr, cpu.Y, cpu.SP)
+}
+
+//TAX ... X = A
+func (cpu *CPU) TAX() {
+	cpu.X = cpu.A
+	cpu.checkAndSetZeroFlag(cpu.X)
+	cpu.checkAndSetNegativeFlag(cpu.X)
+}
+
+//TAY ... Y = A
+func (cpu *CPU) TAY() {
+	cpu.Y = cpu.A
+	cpu.checkAndSetZeroFlag(cpu.Y)
+	cpu.checkAndSetNegativeFlag(cpu.Y)
+}
+
+//TSX ... X = S
+func (cpu *CPU) TSX() {
+	cpu.X = cpu.SP
+	cpu.checkAndSetZeroFlag(cpu.X)
+	cpu.checkAndSetNegativeFlag(cpu.X)
+}
+
+//TXA ... A = X
+func (cpu *CPU) TXA() {
+	cpu.A = cpu.X
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+}
+
+//TXS ... S = X
+func (cpu *CPU) TXS() {
+	cpu.SP = cpu.X
+}
+
+//TYA ... A = Y
+func (cpu *CPU) TYA() {
+	cpu.A = cpu.Y
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+}
+
+//XAA ... Also known as ANE. A = (A | Magic) & X & M. Unstable, the constant
+//depends on the chip and temperature (UNOFFICIAL)
+func (cpu *CPU) XAA(addr uint16) {
+	cpu.A = (cpu.A | cpu.Magic) & cpu.X & cpu.read(addr)
+	cpu.checkAndSetZeroFlag(cpu.A)
+	cpu.checkAndSetNegativeFlag(cpu.A)
+}
@@ -0,0 +1,467 @@
+package nes
+
+import (
+	"fmt"
+	"testing"
+)
+
+//flatMemory ... 64KB of RAM with no memory map, for testing the CPU alone
+type flatMemory [0x10000]byte
+
+func (m *flatMemory) read(addr uint16) byte {
+	return m[addr]
+}
+
+func (m *flatMemory) write(addr uint16, val byte) {
+	m[addr] = val
+}
+
+//TestOperandAddress ... Wraparound of every indexed and indirect addressing
+//mode. The instruction is placed at $8000 and mem is loaded before resolving.
+func TestOperandAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        addressingMode
+		operand     []byte
+		x, y        byte
+		mem         map[uint16]byte
+		want        uint16
+		pageCrossed bool
+	}{
+		{name: "zp", mode: zeroPage, operand: []byte{0x42}, want: 0x0042},
+		{name: "zp,X", mode: zeroPageX, operand: []byte{0x10}, x: 0x05, want: 0x0015},
+		{name: "zp,X wraps in page zero", mode: zeroPageX, operand: []byte{0x80}, x: 0xFF, want: 0x007F},
+		{name: "zp,Y", mode: zeroPageY, operand: []byte{0x10}, y: 0x05, want: 0x0015},
+		{name: "zp,Y wraps in page zero", mode: zeroPageY, operand: []byte{0xFF}, y: 0x02, want: 0x0001},
+		{name: "abs", mode: absolute, operand: []byte{0x34, 0x12}, want: 0x1234},
+		{name: "abs,X", mode: absoluteX, operand: []byte{0x00, 0x02}, x: 0x10, want: 0x0210},
+		{name: "abs,X crosses page", mode: absoluteX, operand: []byte{0xFF, 0x02}, x: 0x01, want: 0x0300, pageCrossed: true},
+		{name: "abs,X wraps at $FFFF", mode: absoluteX, operand: []byte{0xFF, 0xFF}, x: 0x01, want: 0x0000, pageCrossed: true},
+		{name: "abs,Y", mode: absoluteY, operand: []byte{0x00, 0x02}, y: 0x10, want: 0x0210},
+		{name: "abs,Y wraps at $FFFF", mode: absoluteY, operand: []byte{0xF0, 0xFF}, y: 0x20, want: 0x0010, pageCrossed: true},
+		{
+			name:    "(zp,X)",
+			mode:    indexedIndirect,
+			operand: []byte{0x20},
+			x:       0x04,
+			mem:     map[uint16]byte{0x24: 0x74, 0x25: 0x20},
+			want:    0x2074,
+		},
+		{
+			name:    "(zp,X) index wraps in page zero",
+			mode:    indexedIndirect,
+			operand: []byte{0x80},
+			x:       0x90,
+			mem:     map[uint16]byte{0x10: 0x00, 0x11: 0x03},
+			want:    0x0300,
+		},
+		{
+			name:    "(zp,X) pointer at $FF wraps to $00",
+			mode:    indexedIndirect,
+			operand: []byte{0xFE},
+			x:       0x01,
+			mem:     map[uint16]byte{0xFF: 0x34, 0x00: 0x12, 0x100: 0x56},
+			want:    0x1234,
+		},
+		{
+			name:    "(zp),Y",
+			mode:    indirectIndexed,
+			operand: []byte{0x86},
+			y:       0x10,
+			mem:     map[uint16]byte{0x86: 0x28, 0x87: 0x40},
+			want:    0x4038,
+		},
+		{
+			name:    "(zp),Y pointer at $FF wraps to $00",
+			mode:    indirectIndexed,
+			operand: []byte{0xFF},
+			y:       0x01,
+			mem:     map[uint16]byte{0xFF: 0x00, 0x00: 0x03, 0x100: 0x56},
+			want:    0x0301,
+		},
+		{
+			name:        "(zp),Y crosses page",
+			mode:        indirectIndexed,
+			operand:     []byte{0x40},
+			y:           0x01,
+			mem:         map[uint16]byte{0x40: 0xFF, 0x41: 0x02},
+			want:        0x0300,
+			pageCrossed: true,
+		},
+		{
+			name:        "(zp),Y wraps at $FFFF",
+			mode:        indirectIndexed,
+			operand:     []byte{0x40},
+			y:           0x02,
+			mem:         map[uint16]byte{0x40: 0xFF, 0x41: 0xFF},
+			want:        0x0001,
+			pageCrossed: true,
+		},
+		{
+			name:    "JMP ($xxxx)",
+			mode:    indirect,
+			operand: []byte{0x10, 0x02},
+			mem:     map[uint16]byte{0x0210: 0x34, 0x0211: 0x12},
+			want:    0x1234,
+		},
+		{
+			name:    "JMP ($xxFF) takes the high byte from the same page",
+			mode:    indirect,
+			operand: []byte{0xFF, 0x02},
+			mem:     map[uint16]byte{0x02FF: 0x34, 0x0200: 0x12, 0x0300: 0x56},
+			want:    0x1234,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := &flatMemory{}
+			copy(mem[0x8001:], tt.operand)
+			for addr, val := range tt.mem {
+				mem[addr] = val
+			}
+			cpu := &CPU{bus: mem, X: tt.x, Y: tt.y}
+			i := &Instruction{mode: tt.mode, size: 1 + operandSize[tt.mode]}
+			cpu.PC = 0x8000 + i.size
+
+			if got := cpu.resolveOperand(i); got.addr != tt.want {
+				t.Errorf("address = $%04X, want $%04X", got.addr, tt.want)
+			}
+			if cpu.pageCrossed != tt.pageCrossed {
+				t.Errorf("pageCrossed = %v, want %v", cpu.pageCrossed, tt.pageCrossed)
+			}
+		})
+	}
+}
+
+//TestJMPIndirectPageBug ... The whole instruction, not just the address
+func TestJMPIndirectPageBug(t *testing.T) {
+	mem := &flatMemory{}
+	copy(mem[0x8000:], []byte{0x6C, 0xFF, 0x30})
+	mem[0x30FF], mem[0x3000], mem[0x3100] = 0x80, 0x50, 0x40
+
+	cpu := &CPU{bus: mem, PC: 0x8000}
+	cycles, err := cpu.Step()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpu.PC != 0x5080 {
+		t.Errorf("PC = $%04X, want $5080", cpu.PC)
+	}
+	if cycles != 5 {
+		t.Errorf("took %d cycles, want 5", cycles)
+	}
+}
+
+//TestShiftOperand ... Shifts and rotates act on the accumulator only in
+//accumulator mode, memory at any address is shifted in place
+func TestShiftOperand(t *testing.T) {
+	tests := []struct {
+		name    string
+		program []byte
+		a, mem  byte //A and $000A before
+		carry   bool
+		wantA   byte
+		wantMem byte
+	}{
+		{name: "ASL A", program: []byte{0x0A}, a: 0x81, mem: 0x01, wantA: 0x02, wantMem: 0x01},
+		{name: "ASL $0A", program: []byte{0x06, 0x0A}, a: 0x81, mem: 0x01, wantA: 0x81, wantMem: 0x02},
+		{name: "ASL $000A", program: []byte{0x0E, 0x0A, 0x00}, a: 0x81, mem: 0x01, wantA: 0x81, wantMem: 0x02},
+		{name: "LSR A", program: []byte{0x4A}, a: 0x81, mem: 0x02, wantA: 0x40, wantMem: 0x02},
+		{name: "LSR $0A", program: []byte{0x46, 0x0A}, a: 0x81, mem: 0x02, wantA: 0x81, wantMem: 0x01},
+		{name: "ROL A", program: []byte{0x2A}, a: 0x80, mem: 0x01, carry: true, wantA: 0x01, wantMem: 0x01},
+		{name: "ROL $0A", program: []byte{0x26, 0x0A}, a: 0x80, mem: 0x01, carry: true, wantA: 0x80, wantMem: 0x03},
+		{name: "ROR A", program: []byte{0x6A}, a: 0x01, mem: 0x02, carry: true, wantA: 0x80, wantMem: 0x02},
+		{name: "ROR $0A", program: []byte{0x66, 0x0A}, a: 0x01, mem: 0x02, carry: true, wantA: 0x01, wantMem: 0x81},
+		{name: "ROR $08,X", program: []byte{0x76, 0x08}, a: 0x01, mem: 0x02, wantA: 0x01, wantMem: 0x01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := &flatMemory{}
+			copy(mem[0x8000:], tt.program)
+			mem[0x000A] = tt.mem
+			cpu := &CPU{bus: mem, PC: 0x8000, A: tt.a, X: 0x02}
+			if tt.carry {
+				cpu.P = setBit(cpu.P, 0)
+			}
+			if _, err := cpu.Step(); err != nil {
+				t.Fatal(err)
+			}
+			if cpu.A != tt.wantA {
+				t.Errorf("A = $%02X, want $%02X", cpu.A, tt.wantA)
+			}
+			if mem[0x000A] != tt.wantMem {
+				t.Errorf("$000A = $%02X, want $%02X", mem[0x000A], tt.wantMem)
+			}
+		})
+	}
+}
+
+//readLog ... flatMemory that records every address read
+type readLog struct {
+	flatMemory
+	reads []uint16
+}
+
+func (m *readLog) read(addr uint16) byte {
+	m.reads = append(m.reads, addr)
+	return m.flatMemory[addr]
+}
+
+//TestIGNReads ... Unofficial NOPs with an operand still read it, which matters
+//for registers with read side effects like $2002 and $2007
+func TestIGNReads(t *testing.T) {
+	want := map[addressingMode]uint16{
+		immediate: 0x8001,
+		zeroPage:  0x0002,
+		zeroPageX: 0x0002,
+		absolute:  0x2002,
+		absoluteX: 0x2002,
+	}
+	for op := range instructions {
+		i := &instructions[op]
+		if i.Name != "NOP" || i.mode == implied {
+			continue
+		}
+		mem := &readLog{}
+		copy(mem.flatMemory[0x8000:], []byte{i.opcode, 0x02, 0x20})
+		cpu := &CPU{bus: mem, PC: 0x8000}
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if last := mem.reads[len(mem.reads)-1]; last != want[i.mode] {
+			t.Errorf("$%02X: last read $%04X, want $%04X", i.opcode, last, want[i.mode])
+		}
+	}
+}
+
+//access ... One bus cycle seen by busLog
+type access struct {
+	write bool
+	addr  uint16
+	val   byte
+}
+
+func (a access) String() string {
+	if a.write {
+		return fmt.Sprintf("W $%04X=$%02X", a.addr, a.val)
+	}
+	return fmt.Sprintf("R $%04X=$%02X", a.addr, a.val)
+}
+
+//busLog ... flatMemory that records every read and write in order
+type busLog struct {
+	flatMemory
+	accesses []access
+}
+
+func (m *busLog) read(addr uint16) byte {
+	m.accesses = append(m.accesses, access{addr: addr, val: m.flatMemory[addr]})
+	return m.flatMemory[addr]
+}
+
+func (m *busLog) write(addr uint16, val byte) {
+	m.accesses = append(m.accesses, access{write: true, addr: addr, val: val})
+	m.flatMemory[addr] = val
+}
+
+//TestDummyAccesses ... The exact bus cycles of read-modify-write and indexed
+//instructions, including the dummy read at the address before the carry is
+//added into the high byte and the write of the unmodified value.
+//Reference: https://wiki.nesdev.com/w/index.php/CPU_addressing_modes
+func TestDummyAccesses(t *testing.T) {
+	r := func(addr uint16, val byte) access { return access{addr: addr, val: val} }
+	w := func(addr uint16, val byte) access { return access{write: true, addr: addr, val: val} }
+	tests := []struct {
+		name    string
+		program []byte
+		x       byte
+		want    []access
+	}{
+		{
+			name:    "INC abs",
+			program: []byte{0xEE, 0x34, 0x12},
+			want:    []access{r(0x8000, 0xEE), r(0x8001, 0x34), r(0x8002, 0x12), r(0x1234, 0x41), w(0x1234, 0x41), w(0x1234, 0x42)},
+		},
+		{
+			name:    "ASL abs,X crossing a page",
+			program: []byte{0x1E, 0x34, 0x12},
+			x:       0xCC,
+			want:    []access{r(0x8000, 0x1E), r(0x8001, 0x34), r(0x8002, 0x12), r(0x1200, 0x00), r(0x1300, 0x41), w(0x1300, 0x41), w(0x1300, 0x82)},
+		},
+		{
+			name:    "ASL abs,X",
+			program: []byte{0x1E, 0x34, 0x12},
+			want:    []access{r(0x8000, 0x1E), r(0x8001, 0x34), r(0x8002, 0x12), r(0x1234, 0x41), r(0x1234, 0x41), w(0x1234, 0x41), w(0x1234, 0x82)},
+		},
+		{
+			name:    "LDA abs,X crossing a page",
+			program: []byte{0xBD, 0x34, 0x12},
+			x:       0xCC,
+			want:    []access{r(0x8000, 0xBD), r(0x8001, 0x34), r(0x8002, 0x12), r(0x1200, 0x00), r(0x1300, 0x41)},
+		},
+		{
+			name:    "LDA abs,X",
+			program: []byte{0xBD, 0x34, 0x12},
+			want:    []access{r(0x8000, 0xBD), r(0x8001, 0x34), r(0x8002, 0x12), r(0x1234, 0x41)},
+		},
+		{
+			name:    "STA abs,X crossing a page",
+			program: []byte{0x9D, 0x34, 0x12},
+			x:       0xCC,
+			want:    []access{r(0x8000, 0x9D), r(0x8001, 0x34), r(0x8002, 0x12), r(0x1200, 0x00), w(0x1300, 0x07)},
+		},
+		{
+			name:    "STA abs,X",
+			program: []byte{0x9D, 0x34, 0x12},
+			want:    []access{r(0x8000, 0x9D), r(0x8001, 0x34), r(0x8002, 0x12), r(0x1234, 0x41), w(0x1234, 0x07)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := &busLog{}
+			copy(mem.flatMemory[0x8000:], tt.program)
+			mem.flatMemory[0x1234], mem.flatMemory[0x1300] = 0x41, 0x41
+			cpu := &CPU{bus: mem, PC: 0x8000, A: 0x07, X: tt.x}
+			if _, err := cpu.Step(); err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(mem.accesses) != fmt.Sprint(tt.want) {
+				t.Errorf("accesses\n%v\nwant\n%v", mem.accesses, tt.want)
+			}
+		})
+	}
+}
+
+//interruptMemory ... flatMemory with the NMI vector at $A000, RESET at $B000
+//and IRQ/BRK at $C000, and a program at $8000
+func interruptMemory(program ...byte) *flatMemory {
+	mem := &flatMemory{}
+	copy(mem[0x8000:], program)
+	copy(mem[0xFFFA:], []byte{0x00, 0xA0, 0x00, 0xB0, 0x00, 0xC0})
+	return mem
+}
+
+//TestInterrupts ... The interrupt sequences push PC and P, with B telling BRK
+//apart from IRQ and NMI, and take 7 cycles. RESET suppresses the writes.
+func TestInterrupts(t *testing.T) {
+	step := func(cpu *CPU) int {
+		cycles, err := cpu.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cycles
+	}
+	tests := []struct {
+		name      string
+		program   []byte
+		run       func(cpu *CPU) int
+		wantPC    uint16
+		wantStack [3]byte //$01FB-$01FD: P, PC low, PC high
+	}{
+		{
+			name: "NMI",
+			run: func(cpu *CPU) int {
+				cpu.setNMI(true)
+				cpu.pollInterrupts()
+				return step(cpu)
+			},
+			wantPC:    0xA000,
+			wantStack: [3]byte{0x20, 0x00, 0x80},
+		},
+		{
+			name: "IRQ",
+			run: func(cpu *CPU) int {
+				cpu.setIRQ(irqMapper, true)
+				cpu.pollInterrupts()
+				return step(cpu)
+			},
+			wantPC:    0xC000,
+			wantStack: [3]byte{0x20, 0x00, 0x80},
+		},
+		{
+			name:      "BRK",
+			program:   []byte{0x00, 0xFF},
+			run:       step,
+			wantPC:    0xC000,
+			wantStack: [3]byte{0x30, 0x02, 0x80},
+		},
+		{
+			name:    "NMI hijacks BRK",
+			program: []byte{0x00, 0xFF},
+			run: func(cpu *CPU) int {
+				cpu.setNMI(true) //after the poll, so BRK runs first
+				return step(cpu)
+			},
+			wantPC:    0xA000,
+			wantStack: [3]byte{0x30, 0x02, 0x80},
+		},
+		{
+			name: "NMI hijacks IRQ",
+			run: func(cpu *CPU) int {
+				cpu.setIRQ(irqMapper, true)
+				cpu.pollInterrupts()
+				cpu.setNMI(true)
+				return step(cpu)
+			},
+			wantPC:    0xA000,
+			wantStack: [3]byte{0x20, 0x00, 0x80},
+		},
+		{
+			name: "RESET",
+			run: func(cpu *CPU) int {
+				cpu.reset()
+				return int(cpu.Cycles)
+			},
+			wantPC:    0xB000,
+			wantStack: [3]byte{0xEE, 0xEE, 0xEE},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := interruptMemory(tt.program...)
+			copy(mem[0x01FB:], []byte{0xEE, 0xEE, 0xEE})
+			cpu := &CPU{bus: mem, PC: 0x8000, SP: 0xFD, P: 0x20}
+
+			if cycles := tt.run(cpu); cycles != 7 {
+				t.Errorf("took %d cycles, want 7", cycles)
+			}
+			if cpu.PC != tt.wantPC {
+				t.Errorf("PC = $%04X, want $%04X", cpu.PC, tt.wantPC)
+			}
+			if cpu.SP != 0xFA {
+				t.Errorf("SP = $%02X, want $FA", cpu.SP)
+			}
+			if !hasBit(cpu.P, 2) {
+				t.Error("I flag clear")
+			}
+			if stack := [3]byte(mem[0x01FB:0x01FE]); stack != tt.wantStack {
+				t.Errorf("stack = % X, want % X", stack, tt.wantStack)
+			}
+		})
+	}
+}
+
+//TestMaskedIRQ ... An IRQ asserted while I is set stays pending and is taken
+//once CLI has cleared I, one instruction late since CLI changes I after the poll
+func TestMaskedIRQ(t *testing.T) {
+	mem := interruptMemory(0xEA, 0x58, 0xEA, 0xEA) //NOP, CLI, NOP, NOP
+	cpu := &CPU{bus: mem, PC: 0x8000, SP: 0xFD, P: 0x24, irqInhibit: true}
+	cpu.setIRQ(irqMapper, true)
+
+	for _, want := range []uint16{0x8001, 0x8002, 0x8003, 0xC000} {
+		cpu.pollInterrupts()
+		if _, err := cpu.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if cpu.PC != want {
+			t.Fatalf("PC = $%04X, want $%04X", cpu.PC, want)
+		}
+	}
+	if ret := uint16(mem[0x01FD])<<8 | uint16(mem[0x01FC]); ret != 0x8003 {
+		t.Errorf("pushed PC = $%04X, want $8003", ret)
+	}
+}
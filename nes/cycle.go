@@ -0,0 +1,128 @@
+package nes
+
+import (
+	"errors"
+	"iter"
+)
+
+//cycleCore ... Runs the CPU one bus access per cycle. The instruction-stepped
+//core executes a whole instruction and then clocks the rest of the console for
+//the cycles it took. Here Step runs as a coroutine that is suspended at every
+//bus access, so the PPU, APU and mapper are clocked between accesses and see
+//each one on the cycle it happens, e.g. a $2002 read racing the VBlank flag.
+//
+//Internal cycles that make no bus access in this emulation, such as those of
+//implied and stack instructions, are taken as dummy reads of PC after the
+//instruction's last access. The halt and alignment cycles of a DMA stall make
+//no access, OAM DMA makes one access per cycle of its transfer like the CPU.
+type cycleCore struct {
+	cpu      *CPU
+	bus      Memory //The console's bus, the CPU is connected to the core instead
+	boundary func() //Called before each instruction starts
+
+	next func() (cycleEvent, bool)
+	stop func()
+
+	yield    func(cycleEvent) bool
+	owed     bool //An access was made and its cycle hasn't been reported yet
+	accesses int  //Accesses made by the current instruction
+}
+
+//cycleEvent ... Reported at the end of each cycle
+type cycleEvent struct {
+	done bool //Last cycle of an instruction, interrupt or DMA stall cycle
+	err  error
+}
+
+//errCoreStopped ... Unwinds an instruction abandoned by RESET
+var errCoreStopped = errors.New("cycle core stopped")
+
+func newCycleCore(cpu *CPU, bus Memory, boundary func()) *cycleCore {
+	core := &cycleCore{
+		cpu:      cpu,
+		bus:      bus,
+		boundary: boundary,
+	}
+	core.attach()
+	return core
+}
+
+//attach ... Connects the CPU to the core and starts a fresh coroutine
+func (core *cycleCore) attach() {
+	core.next, core.stop = iter.Pull(core.run)
+	core.cpu.bus = core
+}
+
+//detach ... Abandons the instruction in progress and reconnects the CPU
+//directly to the bus, e.g. so the RESET sequence can run outside the coroutine
+func (core *cycleCore) detach() {
+	core.stop()
+	core.cpu.bus = core.bus
+}
+
+//tick ... Runs the CPU up to the end of its next cycle
+func (core *cycleCore) tick() cycleEvent {
+	ev, _ := core.next()
+	return ev
+}
+
+func (core *cycleCore) run(yield func(cycleEvent) bool) {
+	defer func() {
+		if r := recover(); r != nil && r != errCoreStopped {
+			panic(r)
+		}
+	}()
+	core.yield = yield
+	core.owed = false
+	for {
+		core.boundary()
+		stalled := core.cpu.stallCycles > 0 || core.cpu.halted
+		core.accesses = 0
+		cycles, err := core.cpu.Step()
+		if cycles == 1 {
+			// the first cycle is also the last, poll as it starts
+			core.cpu.pollInterrupts()
+		}
+		for ; core.accesses < cycles; core.accesses++ {
+			core.endCycle()
+			if !stalled {
+				core.bus.read(core.cpu.PC)
+				core.cpu.writing = false
+			}
+			core.owed = true
+		}
+		core.owed = false
+		if !yield(cycleEvent{done: true, err: err}) {
+			panic(errCoreStopped)
+		}
+	}
+}
+
+//endCycle ... Suspends the coroutine once the access made in the current cycle
+//has to be reported, so that the next access lands in a new cycle
+func (core *cycleCore) endCycle() {
+	if !core.owed {
+		return
+	}
+	core.owed = false
+	if !core.yield(cycleEvent{}) {
+		panic(errCoreStopped)
+	}
+	// polling as every cycle starts leaves the next Step with the poll made
+	// before the last cycle, after the second to last was clocked
+	core.cpu.pollInterrupts()
+}
+
+func (core *cycleCore) read(addr uint16) byte {
+	core.endCycle()
+	core.accesses++
+	core.owed = true
+	return core.bus.read(addr)
+}
+
+func (core *cycleCore) write(addr uint16, val byte) {
+	core.endCycle()
+	core.accesses++
+	core.owed = true
+	core.bus.write(addr, val)
+}
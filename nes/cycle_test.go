@@ -0,0 +1,219 @@
+package nes
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+//nromImage ... Builds a 16KB NROM image with prg at $C000, which is also the
+//RESET vector, and the NMI and IRQ vectors pointing at nmi
+func nromImage(prg []byte, nmi uint16) []byte {
+	header := []byte{'N', 'E', 'S', 0x1A, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	rom := make([]byte, prgUnitSize)
+	copy(rom, prg)
+	rom[0x3FFA], rom[0x3FFB] = byte(nmi), byte(nmi>>8)
+	rom[0x3FFC], rom[0x3FFD] = 0x00, 0xC0
+	rom[0x3FFE], rom[0x3FFF] = byte(nmi), byte(nmi>>8)
+	image := append(header, rom...)
+	return append(image, make([]byte, chrUnitSize)...)
+}
+
+//traceFrames ... Runs a console for a number of frames and returns its trace
+func traceFrames(t *testing.T, image []byte, cycleStepped bool, frames int) []string {
+	var out bytes.Buffer
+	console, err := New(image, Options{Tracer: NewTracer(&out), CycleStepped: cycleStepped})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < frames; i++ {
+		if _, _, err := console.RunFrame(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return strings.Split(out.String(), "\n")
+}
+
+//TestCycleSteppedTrace ... Both cores have to produce the same trace for code
+//whose result doesn't depend on when in an instruction the bus is accessed
+func TestCycleSteppedTrace(t *testing.T) {
+	prg := []byte{
+		0x78,       // C000 SEI
+		0xA2, 0xFF, // C001 LDX #$FF
+		0x9A,       // C003 TXS
+		0xA9, 0x80, // C004 LDA #$80
+		0x8D, 0x00, 0x20, // C006 STA $2000 (enable NMI)
+		0xE6, 0x00, // C009 INC $00
+		0xA6, 0x00, // C00B LDX $00
+		0xB5, 0x10, // C00D LDA $10,X
+		0x9D, 0x00, 0x03, // C00F STA $0300,X
+		0xBD, 0xFF, 0x02, // C012 LDA $02FF,X
+		0x20, 0x1C, 0xC0, // C015 JSR $C01C
+		0x4C, 0x09, 0xC0, // C018 JMP $C009
+		0xEA,             // C01B NOP
+		0x48,             // C01C PHA
+		0x1E, 0x00, 0x03, // C01D ASL $0300,X
+		0x68,       // C020 PLA
+		0x60,       // C021 RTS
+		0xE6, 0x01, // C022 INC $01 (NMI)
+		0x26, 0x02, // C024 ROL $02
+		0x40, // C026 RTI
+	}
+	image := nromImage(prg, 0xC022)
+
+	want := traceFrames(t, image, false, 3)
+	got := traceFrames(t, image, true, 3)
+	for i := range want {
+		if i >= len(got) {
+			t.Fatalf("cycle-stepped trace ends after %d lines, want %d", len(got), len(want))
+		}
+		if want[i] != got[i] {
+			t.Fatalf("traces diverge at line %d\nwant: %s\ngot:  %s", i+1, want[i], got[i])
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("cycle-stepped trace has %d lines, want %d", len(got), len(want))
+	}
+}
+
+//TestCloseReleasesCore ... Closing a cycle-stepped console frees the
+//coroutine it runs the CPU on
+func TestCloseReleasesCore(t *testing.T) {
+	before := runtime.NumGoroutine()
+	consoles := make([]*NES, 50)
+	for i := range consoles {
+		console, err := New(nromImage([]byte{0x4C, 0x00, 0xC0}, 0xC000), Options{CycleStepped: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := console.Tick(); err != nil {
+			t.Fatal(err)
+		}
+		consoles[i] = console
+	}
+	for _, console := range consoles {
+		console.Close()
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("%d goroutines before creating the consoles, %d after closing them", before, after)
+	}
+
+	console := consoles[0]
+	console.Reset()
+	if _, _, err := console.RunFrame(); err != ErrClosed {
+		t.Errorf("RunFrame after Close: got %v, want ErrClosed", err)
+	}
+	if err := console.Tick(); err != ErrClosed {
+		t.Errorf("Tick after Close: got %v, want ErrClosed", err)
+	}
+}
+
+//TestInterruptPoll ... An NMI raised before an instruction's last cycle is
+//taken after it, one raised during the last cycle waits for the next one
+func TestInterruptPoll(t *testing.T) {
+	tests := []struct {
+		name  string
+		ticks int //Cycles of the first NOP run before NMI is raised
+		want  []string
+	}{
+		{name: "raised on second to last cycle", ticks: 1, want: []string{"C000", "C010", "C011"}},
+		{name: "raised on last cycle", ticks: 2, want: []string{"C000", "C001", "C010"}},
+	}
+
+	for _, tt := range tests {
+		for _, cycleStepped := range []bool{false, true} {
+			name := tt.name
+			if cycleStepped {
+				name += ", cycle-stepped"
+			}
+			t.Run(name, func(t *testing.T) {
+				out := &lineWriter{}
+				prg := bytes.Repeat([]byte{0xEA}, 0x20) // NOPs, the handler included
+				console, err := New(nromImage(prg, 0xC010), Options{Tracer: NewTracer(out), CycleStepped: cycleStepped})
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer console.Close()
+				for i := 0; i < tt.ticks; i++ {
+					if err := console.Tick(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				console.cpu.setNMI(true)
+				for len(out.lines) < len(tt.want) {
+					if _, err := console.StepInstruction(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				for i, want := range tt.want {
+					if got := out.lines[i][:4]; got != want {
+						t.Errorf("instruction %d at %s, want %s", i, got, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+//accessCounter ... Counts the bus accesses made through it
+type accessCounter struct {
+	Memory
+	accesses int
+}
+
+func (c *accessCounter) read(addr uint16) byte {
+	c.accesses++
+	return c.Memory.read(addr)
+}
+
+func (c *accessCounter) write(addr uint16, val byte) {
+	c.accesses++
+	c.Memory.write(addr, val)
+}
+
+//TestOAMDMACycles ... OAM DMA makes one access per cycle of its transfer, and
+//none on its halt and alignment cycles
+func TestOAMDMACycles(t *testing.T) {
+	prg := []byte{
+		0xA9, 0xC1, // C000 LDA #$C1
+		0x8D, 0x14, 0x40, // C002 STA $4014
+		0x4C, 0x05, 0xC0, // C005 JMP $C005
+	}
+	image := nromImage(prg, 0xC000)
+	page := image[headerSize+0x100 : headerSize+0x200]
+	for i := range page {
+		page[i] = byte(i) ^ 0x5A
+	}
+
+	console, err := New(image, Options{CycleStepped: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer console.Close()
+	counter := &accessCounter{Memory: console.core.bus}
+	console.core.bus = counter
+
+	idle := 0
+	for i := 0; i < 2+4+514+3; i++ {
+		counter.accesses = 0
+		if err := console.Tick(); err != nil {
+			t.Fatal(err)
+		}
+		switch counter.accesses {
+		case 0:
+			idle++
+		case 1:
+		default:
+			t.Fatalf("cycle %d made %d accesses", i, counter.accesses)
+		}
+	}
+	if idle < 1 || idle > 2 {
+		t.Errorf("%d cycles without an access, want the halt and at most one alignment cycle", idle)
+	}
+	for i, want := range page {
+		if got := console.ppu.oam[i]; got != want {
+			t.Fatalf("OAM[$%02X] = $%02X, want $%02X", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package nes
+
+import "fmt"
+
+//nestestNames ... Mnemonics the nestest log uses where ours differ
+var nestestNames = map[string]string{
+	"AAX": "SAX",
+	"ASO": "SLO",
+	"LSE": "SRE",
+	"ISC": "ISB",
+}
+
+//disassemble ... Decodes the instruction at addr in the style of the nestest
+//log: raw bytes, then mnemonic and operand annotated with the effective
+//address and the value there. Unofficial opcodes are marked with a '*'.
+//Memory is peeked, so disassembling has no side effects on the bus.
+func disassemble(cpu *CPU, b *Bus, addr uint16) (raw string, text string) {
+	i := &instructions[b.peek(addr)]
+	mode := i.mode
+	size := operandSize[mode]
+
+	raw = fmt.Sprintf("%02X", i.opcode)
+	for i := uint16(1); i <= size; i++ {
+		raw += fmt.Sprintf(" %02X", b.peek(addr+i))
+	}
+
+	name := i.Name
+	if n, ok := nestestNames[name]; ok {
+		name = n
+	}
+	if i.unofficial {
+		name = "*" + name
+	}
+
+	lo := b.peek(addr + 1)
+	hi := b.peek(addr + 2)
+	abs := uint16(hi)<<8 | uint16(lo)
+	// zero page pointers wrap within page zero
+	peekPointer := func(zp byte) uint16 {
+		return uint16(b.peek(uint16(zp+1)))<<8 | uint16(b.peek(uint16(zp)))
+	}
+
+	var operand string
+	switch mode {
+	case implied:
+	case accumulator:
+		operand = "A"
+	case immediate:
+		operand = fmt.Sprintf("#$%02X", lo)
+	case zeroPage:
+		operand = fmt.Sprintf("$%02X = %02X", lo, b.peek(uint16(lo)))
+	case zeroPageX:
+		ea := lo + cpu.X
+		operand = fmt.Sprintf("$%02X,X @ %02X = %02X", lo, ea, b.peek(uint16(ea)))
+	case zeroPageY:
+		ea := lo + cpu.Y
+		operand = fmt.Sprintf("$%02X,Y @ %02X = %02X", lo, ea, b.peek(uint16(ea)))
+	case absolute:
+		if i.Name == "JMP" || i.Name == "JSR" {
+			operand = fmt.Sprintf("$%04X", abs)
+		} else {
+			operand = fmt.Sprintf("$%04X = %02X", abs, b.peek(abs))
+		}
+	case absoluteX:
+		ea := abs + uint16(cpu.X)
+		operand = fmt.Sprintf("$%04X,X @ %04X = %02X", abs, ea, b.peek(ea))
+	case absoluteY:
+		ea := abs + uint16(cpu.Y)
+		operand = fmt.Sprintf("$%04X,Y @ %04X = %02X", abs, ea, b.peek(ea))
+	case indirect:
+		// the high byte is fetched without carrying into the page
+		target := uint16(b.peek(abs&0xFF00|(abs+1)&0x00FF))<<8 | uint16(b.peek(abs))
+		operand = fmt.Sprintf("($%04X) = %04X", abs, target)
+	case indexedIndirect:
+		ptr := lo + cpu.X
+		ea := peekPointer(ptr)
+		operand = fmt.Sprintf("($%02X,X) @ %02X = %04X = %02X", lo, ptr, ea, b.peek(ea))
+	case indirectIndexed:
+		base := peekPointer(lo)
+		ea := base + uint16(cpu.Y)
+		operand = fmt.Sprintf("($%02X),Y = %04X @ %04X = %02X", lo, base, ea, b.peek(ea))
+	case relative:
+		operand = fmt.Sprintf("$%04X", addr+2+uint16(int8(lo)))
+	}
+
+	text = name
+	if operand != "" {
+		text += " " + operand
+	}
+	return raw, text
+}
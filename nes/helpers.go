@@ -0,0 +1,26 @@
+package nes
+
+func setBit(b byte, pos uint8) byte {
+	b |= (1 << pos)
+	return b
+}
+
+func clearBit(b byte, pos uint8) byte {
+	mask := byte(^(1 << pos))
+	b &= mask
+	return b
+}
+
+func hasBit(b byte, pos uint8) bool {
+	val := b & (1 << pos)
+	return (val > 0)
+}
+
+func reverseBits(b byte) byte {
+	var r byte
+	for i := 0; i < 8; i++ {
+		r = r<<1 | b&1
+		b >>= 1
+	}
+	return r
+}
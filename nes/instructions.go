@@ -0,0 +1,349 @@
+package nes
+
+//addressingMode ... How an instruction locates its operand
+//Reference: https://wiki.nesdev.com/w/index.php/CPU_addressing_modes
+type addressingMode byte
+
+const (
+	implied addressingMode = iota
+	accumulator
+	immediate
+	zeroPage
+	zeroPageX
+	zeroPageY
+	absolute
+	absoluteX
+	absoluteY
+	indirect
+	indexedIndirect //(d,x)
+	indirectIndexed //(d),y
+	relative
+)
+
+//operandSize ... Bytes following the opcode for each addressing mode
+var operandSize = [...]uint16{
+	implied:         0,
+	accumulator:     0,
+	immediate:       1,
+	zeroPage:        1,
+	zeroPageX:       1,
+	zeroPageY:       1,
+	absolute:        2,
+	absoluteX:       2,
+	absoluteY:       2,
+	indirect:        2,
+	indexedIndirect: 1,
+	indirectIndexed: 1,
+	relative:        1,
+}
+
+//Instruction ... Represents an instruction
+type Instruction struct {
+	Name       string
+	opcode     byte
+	mode       addressingMode
+	size       uint16 //Opcode plus operand bytes, derived from mode
+	numCycles  int
+	pageCycle  bool //Takes an extra cycle when indexing crosses a page
+	unofficial bool //Not part of the documented 6502 instruction set
+	delaysIRQ  bool //Changes I after the interrupt poll, see pollIRQ
+	execute    func(cpu *CPU, op operand)
+}
+
+//noOperand ... Adapts an instruction that ignores its operand to the dispatch
+//table's signature
+func noOperand(f func(cpu *CPU)) func(cpu *CPU, op operand) {
+	return func(cpu *CPU, _ operand) { f(cpu) }
+}
+
+//withAddress ... Adapts an instruction that reads or writes memory to the
+//dispatch table's signature, immediates are the byte after the opcode
+func withAddress(f func(cpu *CPU, addr uint16)) func(cpu *CPU, op operand) {
+	return func(cpu *CPU, op operand) { f(cpu, op.addr) }
+}
+
+//instructions ... Dispatch table indexed by opcode, covering every official and
+//unofficial opcode. Step reports an entry without an execute function as an
+//UnknownOpcodeError.
+//Reference: http://www.oxyron.de/html/opcodes02.html
+var instructions = [256]Instruction{
+	0x00: {Name: "BRK", mode: implied, numCycles: 7, execute: noOperand((*CPU).BRK)},
+	0x01: {Name: "ORA", mode: indexedIndirect, numCycles: 6, execute: withAddress((*CPU).ORA)},
+	0x02: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0x03: {Name: "ASO", mode: indexedIndirect, numCycles: 8, unofficial: true, execute: withAddress((*CPU).ASO)},
+	0x04: {Name: "NOP", mode: zeroPage, numCycles: 3, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x05: {Name: "ORA", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).ORA)},
+	0x06: {Name: "ASL", mode: zeroPage, numCycles: 5, execute: (*CPU).ASL},
+	0x07: {Name: "ASO", mode: zeroPage, numCycles: 5, unofficial: true, execute: withAddress((*CPU).ASO)},
+	0x08: {Name: "PHP", mode: implied, numCycles: 3, execute: noOperand((*CPU).PHP)},
+	0x09: {Name: "ORA", mode: immediate, numCycles: 2, execute: withAddress((*CPU).ORA)},
+	0x0A: {Name: "ASL", mode: accumulator, numCycles: 2, execute: (*CPU).ASL},
+	0x0B: {Name: "ANC", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).ANC)},
+	0x0C: {Name: "NOP", mode: absolute, numCycles: 4, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x0D: {Name: "ORA", mode: absolute, numCycles: 4, execute: withAddress((*CPU).ORA)},
+	0x0E: {Name: "ASL", mode: absolute, numCycles: 6, execute: (*CPU).ASL},
+	0x0F: {Name: "ASO", mode: absolute, numCycles: 6, unofficial: true, execute: withAddress((*CPU).ASO)},
+
+	0x10: {Name: "BPL", mode: relative, numCycles: 2, execute: withAddress((*CPU).BPL)},
+	0x11: {Name: "ORA", mode: indirectIndexed, numCycles: 5, pageCycle: true, execute: withAddress((*CPU).ORA)},
+	0x12: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0x13: {Name: "ASO", mode: indirectIndexed, numCycles: 8, unofficial: true, execute: withAddress((*CPU).ASO)},
+	0x14: {Name: "NOP", mode: zeroPageX, numCycles: 4, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x15: {Name: "ORA", mode: zeroPageX, numCycles: 4, execute: withAddress((*CPU).ORA)},
+	0x16: {Name: "ASL", mode: zeroPageX, numCycles: 6, execute: (*CPU).ASL},
+	0x17: {Name: "ASO", mode: zeroPageX, numCycles: 6, unofficial: true, execute: withAddress((*CPU).ASO)},
+	0x18: {Name: "CLC", mode: implied, numCycles: 2, execute: noOperand((*CPU).CLC)},
+	0x19: {Name: "ORA", mode: absoluteY, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).ORA)},
+	0x1A: {Name: "NOP", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).NOP)},
+	0x1B: {Name: "ASO", mode: absoluteY, numCycles: 7, unofficial: true, execute: withAddress((*CPU).ASO)},
+	0x1C: {Name: "NOP", mode: absoluteX, numCycles: 4, pageCycle: true, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x1D: {Name: "ORA", mode: absoluteX, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).ORA)},
+	0x1E: {Name: "ASL", mode: absoluteX, numCycles: 7, execute: (*CPU).ASL},
+	0x1F: {Name: "ASO", mode: absoluteX, numCycles: 7, unofficial: true, execute: withAddress((*CPU).ASO)},
+
+	0x20: {Name: "JSR", mode: absolute, numCycles: 6, execute: withAddress((*CPU).JSR)},
+	0x21: {Name: "AND", mode: indexedIndirect, numCycles: 6, execute: withAddress((*CPU).AND)},
+	0x22: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0x23: {Name: "RLA", mode: indexedIndirect, numCycles: 8, unofficial: true, execute: withAddress((*CPU).RLA)},
+	0x24: {Name: "BIT", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).BIT)},
+	0x25: {Name: "AND", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).AND)},
+	0x26: {Name: "ROL", mode: zeroPage, numCycles: 5, execute: (*CPU).ROL},
+	0x27: {Name: "RLA", mode: zeroPage, numCycles: 5, unofficial: true, execute: withAddress((*CPU).RLA)},
+	0x28: {Name: "PLP", mode: implied, numCycles: 4, delaysIRQ: true, execute: noOperand((*CPU).PLP)},
+	0x29: {Name: "AND", mode: immediate, numCycles: 2, execute: withAddress((*CPU).AND)},
+	0x2A: {Name: "ROL", mode: accumulator, numCycles: 2, execute: (*CPU).ROL},
+	0x2B: {Name: "ANC", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).ANC)},
+	0x2C: {Name: "BIT", mode: absolute, numCycles: 4, execute: withAddress((*CPU).BIT)},
+	0x2D: {Name: "AND", mode: absolute, numCycles: 4, execute: withAddress((*CPU).AND)},
+	0x2E: {Name: "ROL", mode: absolute, numCycles: 6, execute: (*CPU).ROL},
+	0x2F: {Name: "RLA", mode: absolute, numCycles: 6, unofficial: true, execute: withAddress((*CPU).RLA)},
+
+	0x30: {Name: "BMI", mode: relative, numCycles: 2, execute: withAddress((*CPU).BMI)},
+	0x31: {Name: "AND", mode: indirectIndexed, numCycles: 5, pageCycle: true, execute: withAddress((*CPU).AND)},
+	0x32: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0x33: {Name: "RLA", mode: indirectIndexed, numCycles: 8, unofficial: true, execute: withAddress((*CPU).RLA)},
+	0x34: {Name: "NOP", mode: zeroPageX, numCycles: 4, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x35: {Name: "AND", mode: zeroPageX, numCycles: 4, execute: withAddress((*CPU).AND)},
+	0x36: {Name: "ROL", mode: zeroPageX, numCycles: 6, execute: (*CPU).ROL},
+	0x37: {Name: "RLA", mode: zeroPageX, numCycles: 6, unofficial: true, execute: withAddress((*CPU).RLA)},
+	0x38: {Name: "SEC", mode: implied, numCycles: 2, execute: noOperand((*CPU).SEC)},
+	0x39: {Name: "AND", mode: absoluteY, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).AND)},
+	0x3A: {Name: "NOP", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).NOP)},
+	0x3B: {Name: "RLA", mode: absoluteY, numCycles: 7, unofficial: true, execute: withAddress((*CPU).RLA)},
+	0x3C: {Name: "NOP", mode: absoluteX, numCycles: 4, pageCycle: true, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x3D: {Name: "AND", mode: absoluteX, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).AND)},
+	0x3E: {Name: "ROL", mode: absoluteX, numCycles: 7, execute: (*CPU).ROL},
+	0x3F: {Name: "RLA", mode: absoluteX, numCycles: 7, unofficial: true, execute: withAddress((*CPU).RLA)},
+
+	0x40: {Name: "RTI", mode: implied, numCycles: 6, execute: noOperand((*CPU).RTI)},
+	0x41: {Name: "EOR", mode: indexedIndirect, numCycles: 6, execute: withAddress((*CPU).EOR)},
+	0x42: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0x43: {Name: "LSE", mode: indexedIndirect, numCycles: 8, unofficial: true, execute: withAddress((*CPU).LSE)},
+	0x44: {Name: "NOP", mode: zeroPage, numCycles: 3, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x45: {Name: "EOR", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).EOR)},
+	0x46: {Name: "LSR", mode: zeroPage, numCycles: 5, execute: (*CPU).LSR},
+	0x47: {Name: "LSE", mode: zeroPage, numCycles: 5, unofficial: true, execute: withAddress((*CPU).LSE)},
+	0x48: {Name: "PHA", mode: implied, numCycles: 3, execute: noOperand((*CPU).PHA)},
+	0x49: {Name: "EOR", mode: immediate, numCycles: 2, execute: withAddress((*CPU).EOR)},
+	0x4A: {Name: "LSR", mode: accumulator, numCycles: 2, execute: (*CPU).LSR},
+	0x4B: {Name: "ALR", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).ALR)},
+	0x4C: {Name: "JMP", mode: absolute, numCycles: 3, execute: withAddress((*CPU).JMP)},
+	0x4D: {Name: "EOR", mode: absolute, numCycles: 4, execute: withAddress((*CPU).EOR)},
+	0x4E: {Name: "LSR", mode: absolute, numCycles: 6, execute: (*CPU).LSR},
+	0x4F: {Name: "LSE", mode: absolute, numCycles: 6, unofficial: true, execute: withAddress((*CPU).LSE)},
+
+	0x50: {Name: "BVC", mode: relative, numCycles: 2, execute: withAddress((*CPU).BVC)},
+	0x51: {Name: "EOR", mode: indirectIndexed, numCycles: 5, pageCycle: true, execute: withAddress((*CPU).EOR)},
+	0x52: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0x53: {Name: "LSE", mode: indirectIndexed, numCycles: 8, unofficial: true, execute: withAddress((*CPU).LSE)},
+	0x54: {Name: "NOP", mode: zeroPageX, numCycles: 4, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x55: {Name: "EOR", mode: zeroPageX, numCycles: 4, execute: withAddress((*CPU).EOR)},
+	0x56: {Name: "LSR", mode: zeroPageX, numCycles: 6, execute: (*CPU).LSR},
+	0x57: {Name: "LSE", mode: zeroPageX, numCycles: 6, unofficial: true, execute: withAddress((*CPU).LSE)},
+	0x58: {Name: "CLI", mode: implied, numCycles: 2, delaysIRQ: true, execute: noOperand((*CPU).CLI)},
+	0x59: {Name: "EOR", mode: absoluteY, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).EOR)},
+	0x5A: {Name: "NOP", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).NOP)},
+	0x5B: {Name: "LSE", mode: absoluteY, numCycles: 7, unofficial: true, execute: withAddress((*CPU).LSE)},
+	0x5C: {Name: "NOP", mode: absoluteX, numCycles: 4, pageCycle: true, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x5D: {Name: "EOR", mode: absoluteX, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).EOR)},
+	0x5E: {Name: "LSR", mode: absoluteX, numCycles: 7, execute: (*CPU).LSR},
+	0x5F: {Name: "LSE", mode: absoluteX, numCycles: 7, unofficial: true, execute: withAddress((*CPU).LSE)},
+
+	0x60: {Name: "RTS", mode: implied, numCycles: 6, execute: noOperand((*CPU).RTS)},
+	0x61: {Name: "ADC", mode: indexedIndirect, numCycles: 6, execute: withAddress((*CPU).ADC)},
+	0x62: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0x63: {Name: "RRA", mode: indexedIndirect, numCycles: 8, unofficial: true, execute: withAddress((*CPU).RRA)},
+	0x64: {Name: "NOP", mode: zeroPage, numCycles: 3, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x65: {Name: "ADC", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).ADC)},
+	0x66: {Name: "ROR", mode: zeroPage, numCycles: 5, execute: (*CPU).ROR},
+	0x67: {Name: "RRA", mode: zeroPage, numCycles: 5, unofficial: true, execute: withAddress((*CPU).RRA)},
+	0x68: {Name: "PLA", mode: implied, numCycles: 4, execute: noOperand((*CPU).PLA)},
+	0x69: {Name: "ADC", mode: immediate, numCycles: 2, execute: withAddress((*CPU).ADC)},
+	0x6A: {Name: "ROR", mode: accumulator, numCycles: 2, execute: (*CPU).ROR},
+	0x6B: {Name: "ARR", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).ARR)},
+	0x6C: {Name: "JMP", mode: indirect, numCycles: 5, execute: withAddress((*CPU).JMP)},
+	0x6D: {Name: "ADC", mode: absolute, numCycles: 4, execute: withAddress((*CPU).ADC)},
+	0x6E: {Name: "ROR", mode: absolute, numCycles: 6, execute: (*CPU).ROR},
+	0x6F: {Name: "RRA", mode: absolute, numCycles: 6, unofficial: true, execute: withAddress((*CPU).RRA)},
+
+	0x70: {Name: "BVS", mode: relative, numCycles: 2, execute: withAddress((*CPU).BVS)},
+	0x71: {Name: "ADC", mode: indirectIndexed, numCycles: 5, pageCycle: true, execute: withAddress((*CPU).ADC)},
+	0x72: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0x73: {Name: "RRA", mode: indirectIndexed, numCycles: 8, unofficial: true, execute: withAddress((*CPU).RRA)},
+	0x74: {Name: "NOP", mode: zeroPageX, numCycles: 4, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x75: {Name: "ADC", mode: zeroPageX, numCycles: 4, execute: withAddress((*CPU).ADC)},
+	0x76: {Name: "ROR", mode: zeroPageX, numCycles: 6, execute: (*CPU).ROR},
+	0x77: {Name: "RRA", mode: zeroPageX, numCycles: 6, unofficial: true, execute: withAddress((*CPU).RRA)},
+	0x78: {Name: "SEI", mode: implied, numCycles: 2, delaysIRQ: true, execute: noOperand((*CPU).SEI)},
+	0x79: {Name: "ADC", mode: absoluteY, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).ADC)},
+	0x7A: {Name: "NOP", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).NOP)},
+	0x7B: {Name: "RRA", mode: absoluteY, numCycles: 7, unofficial: true, execute: withAddress((*CPU).RRA)},
+	0x7C: {Name: "NOP", mode: absoluteX, numCycles: 4, pageCycle: true, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x7D: {Name: "ADC", mode: absoluteX, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).ADC)},
+	0x7E: {Name: "ROR", mode: absoluteX, numCycles: 7, execute: (*CPU).ROR},
+	0x7F: {Name: "RRA", mode: absoluteX, numCycles: 7, unofficial: true, execute: withAddress((*CPU).RRA)},
+
+	0x80: {Name: "NOP", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x81: {Name: "STA", mode: indexedIndirect, numCycles: 6, execute: withAddress((*CPU).STA)},
+	0x82: {Name: "NOP", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x83: {Name: "AAX", mode: indexedIndirect, numCycles: 6, unofficial: true, execute: withAddress((*CPU).AAX)},
+	0x84: {Name: "STY", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).STY)},
+	0x85: {Name: "STA", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).STA)},
+	0x86: {Name: "STX", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).STX)},
+	0x87: {Name: "AAX", mode: zeroPage, numCycles: 3, unofficial: true, execute: withAddress((*CPU).AAX)},
+	0x88: {Name: "DEY", mode: implied, numCycles: 2, execute: noOperand((*CPU).DEY)},
+	0x89: {Name: "NOP", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0x8A: {Name: "TXA", mode: implied, numCycles: 2, execute: noOperand((*CPU).TXA)},
+	0x8B: {Name: "XAA", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).XAA)},
+	0x8C: {Name: "STY", mode: absolute, numCycles: 4, execute: withAddress((*CPU).STY)},
+	0x8D: {Name: "STA", mode: absolute, numCycles: 4, execute: withAddress((*CPU).STA)},
+	0x8E: {Name: "STX", mode: absolute, numCycles: 4, execute: withAddress((*CPU).STX)},
+	0x8F: {Name: "AAX", mode: absolute, numCycles: 4, unofficial: true, execute: withAddress((*CPU).AAX)},
+
+	0x90: {Name: "BCC", mode: relative, numCycles: 2, execute: withAddress((*CPU).BCC)},
+	0x91: {Name: "STA", mode: indirectIndexed, numCycles: 6, execute: withAddress((*CPU).STA)},
+	0x92: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0x93: {Name: "SHA", mode: indirectIndexed, numCycles: 6, unofficial: true, execute: withAddress((*CPU).SHA)},
+	0x94: {Name: "STY", mode: zeroPageX, numCycles: 4, execute: withAddress((*CPU).STY)},
+	0x95: {Name: "STA", mode: zeroPageX, numCycles: 4, execute: withAddress((*CPU).STA)},
+	0x96: {Name: "STX", mode: zeroPageY, numCycles: 4, execute: withAddress((*CPU).STX)},
+	0x97: {Name: "AAX", mode: zeroPageY, numCycles: 4, unofficial: true, execute: withAddress((*CPU).AAX)},
+	0x98: {Name: "TYA", mode: implied, numCycles: 2, execute: noOperand((*CPU).TYA)},
+	0x99: {Name: "STA", mode: absoluteY, numCycles: 5, execute: withAddress((*CPU).STA)},
+	0x9A: {Name: "TXS", mode: implied, numCycles: 2, execute: noOperand((*CPU).TXS)},
+	0x9B: {Name: "TAS", mode: absoluteY, numCycles: 5, unofficial: true, execute: withAddress((*CPU).TAS)},
+	0x9C: {Name: "SHY", mode: absoluteX, numCycles: 5, unofficial: true, execute: withAddress((*CPU).SHY)},
+	0x9D: {Name: "STA", mode: absoluteX, numCycles: 5, execute: withAddress((*CPU).STA)},
+	0x9E: {Name: "SHX", mode: absoluteY, numCycles: 5, unofficial: true, execute: withAddress((*CPU).SHX)},
+	0x9F: {Name: "SHA", mode: absoluteY, numCycles: 5, unofficial: true, execute: withAddress((*CPU).SHA)},
+
+	0xA0: {Name: "LDY", mode: immediate, numCycles: 2, execute: withAddress((*CPU).LDY)},
+	0xA1: {Name: "LDA", mode: indexedIndirect, numCycles: 6, execute: withAddress((*CPU).LDA)},
+	0xA2: {Name: "LDX", mode: immediate, numCycles: 2, execute: withAddress((*CPU).LDX)},
+	0xA3: {Name: "LAX", mode: indexedIndirect, numCycles: 6, unofficial: true, execute: withAddress((*CPU).LAX)},
+	0xA4: {Name: "LDY", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).LDY)},
+	0xA5: {Name: "LDA", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).LDA)},
+	0xA6: {Name: "LDX", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).LDX)},
+	0xA7: {Name: "LAX", mode: zeroPage, numCycles: 3, unofficial: true, execute: withAddress((*CPU).LAX)},
+	0xA8: {Name: "TAY", mode: implied, numCycles: 2, execute: noOperand((*CPU).TAY)},
+	0xA9: {Name: "LDA", mode: immediate, numCycles: 2, execute: withAddress((*CPU).LDA)},
+	0xAA: {Name: "TAX", mode: implied, numCycles: 2, execute: noOperand((*CPU).TAX)},
+	0xAB: {Name: "LXA", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).LXA)},
+	0xAC: {Name: "LDY", mode: absolute, numCycles: 4, execute: withAddress((*CPU).LDY)},
+	0xAD: {Name: "LDA", mode: absolute, numCycles: 4, execute: withAddress((*CPU).LDA)},
+	0xAE: {Name: "LDX", mode: absolute, numCycles: 4, execute: withAddress((*CPU).LDX)},
+	0xAF: {Name: "LAX", mode: absolute, numCycles: 4, unofficial: true, execute: withAddress((*CPU).LAX)},
+
+	0xB0: {Name: "BCS", mode: relative, numCycles: 2, execute: withAddress((*CPU).BCS)},
+	0xB1: {Name: "LDA", mode: indirectIndexed, numCycles: 5, pageCycle: true, execute: withAddress((*CPU).LDA)},
+	0xB2: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0xB3: {Name: "LAX", mode: indirectIndexed, numCycles: 5, pageCycle: true, unofficial: true, execute: withAddress((*CPU).LAX)},
+	0xB4: {Name: "LDY", mode: zeroPageX, numCycles: 4, execute: withAddress((*CPU).LDY)},
+	0xB5: {Name: "LDA", mode: zeroPageX, numCycles: 4, execute: withAddress((*CPU).LDA)},
+	0xB6: {Name: "LDX", mode: zeroPageY, numCycles: 4, execute: withAddress((*CPU).LDX)},
+	0xB7: {Name: "LAX", mode: zeroPageY, numCycles: 4, unofficial: true, execute: withAddress((*CPU).LAX)},
+	0xB8: {Name: "CLV", mode: implied, numCycles: 2, execute: noOperand((*CPU).CLV)},
+	0xB9: {Name: "LDA", mode: absoluteY, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).LDA)},
+	0xBA: {Name: "TSX", mode: implied, numCycles: 2, execute: noOperand((*CPU).TSX)},
+	0xBB: {Name: "LAS", mode: absoluteY, numCycles: 4, pageCycle: true, unofficial: true, execute: withAddress((*CPU).LAS)},
+	0xBC: {Name: "LDY", mode: absoluteX, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).LDY)},
+	0xBD: {Name: "LDA", mode: absoluteX, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).LDA)},
+	0xBE: {Name: "LDX", mode: absoluteY, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).LDX)},
+	0xBF: {Name: "LAX", mode: absoluteY, numCycles: 4, pageCycle: true, unofficial: true, execute: withAddress((*CPU).LAX)},
+
+	0xC0: {Name: "CPY", mode: immediate, numCycles: 2, execute: withAddress((*CPU).CPY)},
+	0xC1: {Name: "CMP", mode: indexedIndirect, numCycles: 6, execute: withAddress((*CPU).CMP)},
+	0xC2: {Name: "NOP", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0xC3: {Name: "DCP", mode: indexedIndirect, numCycles: 8, unofficial: true, execute: withAddress((*CPU).DCP)},
+	0xC4: {Name: "CPY", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).CPY)},
+	0xC5: {Name: "CMP", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).CMP)},
+	0xC6: {Name: "DEC", mode: zeroPage, numCycles: 5, execute: withAddress((*CPU).DEC)},
+	0xC7: {Name: "DCP", mode: zeroPage, numCycles: 5, unofficial: true, execute: withAddress((*CPU).DCP)},
+	0xC8: {Name: "INY", mode: implied, numCycles: 2, execute: noOperand((*CPU).INY)},
+	0xC9: {Name: "CMP", mode: immediate, numCycles: 2, execute: withAddress((*CPU).CMP)},
+	0xCA: {Name: "DEX", mode: implied, numCycles: 2, execute: noOperand((*CPU).DEX)},
+	0xCB: {Name: "AXS", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).AXS)},
+	0xCC: {Name: "CPY", mode: absolute, numCycles: 4, execute: withAddress((*CPU).CPY)},
+	0xCD: {Name: "CMP", mode: absolute, numCycles: 4, execute: withAddress((*CPU).CMP)},
+	0xCE: {Name: "DEC", mode: absolute, numCycles: 6, execute: withAddress((*CPU).DEC)},
+	0xCF: {Name: "DCP", mode: absolute, numCycles: 6, unofficial: true, execute: withAddress((*CPU).DCP)},
+
+	0xD0: {Name: "BNE", mode: relative, numCycles: 2, execute: withAddress((*CPU).BNE)},
+	0xD1: {Name: "CMP", mode: indirectIndexed, numCycles: 5, pageCycle: true, execute: withAddress((*CPU).CMP)},
+	0xD2: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0xD3: {Name: "DCP", mode: indirectIndexed, numCycles: 8, unofficial: true, execute: withAddress((*CPU).DCP)},
+	0xD4: {Name: "NOP", mode: zeroPageX, numCycles: 4, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0xD5: {Name: "CMP", mode: zeroPageX, numCycles: 4, execute: withAddress((*CPU).CMP)},
+	0xD6: {Name: "DEC", mode: zeroPageX, numCycles: 6, execute: withAddress((*CPU).DEC)},
+	0xD7: {Name: "DCP", mode: zeroPageX, numCycles: 6, unofficial: true, execute: withAddress((*CPU).DCP)},
+	0xD8: {Name: "CLD", mode: implied, numCycles: 2, execute: noOperand((*CPU).CLD)},
+	0xD9: {Name: "CMP", mode: absoluteY, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).CMP)},
+	0xDA: {Name: "NOP", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).NOP)},
+	0xDB: {Name: "DCP", mode: absoluteY, numCycles: 7, unofficial: true, execute: withAddress((*CPU).DCP)},
+	0xDC: {Name: "NOP", mode: absoluteX, numCycles: 4, pageCycle: true, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0xDD: {Name: "CMP", mode: absoluteX, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).CMP)},
+	0xDE: {Name: "DEC", mode: absoluteX, numCycles: 7, execute: withAddress((*CPU).DEC)},
+	0xDF: {Name: "DCP", mode: absoluteX, numCycles: 7, unofficial: true, execute: withAddress((*CPU).DCP)},
+
+	0xE0: {Name: "CPX", mode: immediate, numCycles: 2, execute: withAddress((*CPU).CPX)},
+	0xE1: {Name: "SBC", mode: indexedIndirect, numCycles: 6, execute: withAddress((*CPU).SBC)},
+	0xE2: {Name: "NOP", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0xE3: {Name: "ISC", mode: indexedIndirect, numCycles: 8, unofficial: true, execute: withAddress((*CPU).ISC)},
+	0xE4: {Name: "CPX", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).CPX)},
+	0xE5: {Name: "SBC", mode: zeroPage, numCycles: 3, execute: withAddress((*CPU).SBC)},
+	0xE6: {Name: "INC", mode: zeroPage, numCycles: 5, execute: withAddress((*CPU).INC)},
+	0xE7: {Name: "ISC", mode: zeroPage, numCycles: 5, unofficial: true, execute: withAddress((*CPU).ISC)},
+	0xE8: {Name: "INX", mode: implied, numCycles: 2, execute: noOperand((*CPU).INX)},
+	0xE9: {Name: "SBC", mode: immediate, numCycles: 2, execute: withAddress((*CPU).SBC)},
+	0xEA: {Name: "NOP", mode: implied, numCycles: 2, execute: noOperand((*CPU).NOP)},
+	0xEB: {Name: "SBC", mode: immediate, numCycles: 2, unofficial: true, execute: withAddress((*CPU).SBC)},
+	0xEC: {Name: "CPX", mode: absolute, numCycles: 4, execute: withAddress((*CPU).CPX)},
+	0xED: {Name: "SBC", mode: absolute, numCycles: 4, execute: withAddress((*CPU).SBC)},
+	0xEE: {Name: "INC", mode: absolute, numCycles: 6, execute: withAddress((*CPU).INC)},
+	0xEF: {Name: "ISC", mode: absolute, numCycles: 6, unofficial: true, execute: withAddress((*CPU).ISC)},
+
+	0xF0: {Name: "BEQ", mode: relative, numCycles: 2, execute: withAddress((*CPU).BEQ)},
+	0xF1: {Name: "SBC", mode: indirectIndexed, numCycles: 5, pageCycle: true, execute: withAddress((*CPU).SBC)},
+	0xF2: {Name: "KIL", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).KIL)},
+	0xF3: {Name: "ISC", mode: indirectIndexed, numCycles: 8, unofficial: true, execute: withAddress((*CPU).ISC)},
+	0xF4: {Name: "NOP", mode: zeroPageX, numCycles: 4, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0xF5: {Name: "SBC", mode: zeroPageX, numCycles: 4, execute: withAddress((*CPU).SBC)},
+	0xF6: {Name: "INC", mode: zeroPageX, numCycles: 6, execute: withAddress((*CPU).INC)},
+	0xF7: {Name: "ISC", mode: zeroPageX, numCycles: 6, unofficial: true, execute: withAddress((*CPU).ISC)},
+	0xF8: {Name: "SED", mode: implied, numCycles: 2, execute: noOperand((*CPU).SED)},
+	0xF9: {Name: "SBC", mode: absoluteY, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).SBC)},
+	0xFA: {Name: "NOP", mode: implied, numCycles: 2, unofficial: true, execute: noOperand((*CPU).NOP)},
+	0xFB: {Name: "ISC", mode: absoluteY, numCycles: 7, unofficial: true, execute: withAddress((*CPU).ISC)},
+	0xFC: {Name: "NOP", mode: absoluteX, numCycles: 4, pageCycle: true, unofficial: true, execute: withAddress((*CPU).IGN)},
+	0xFD: {Name: "SBC", mode: absoluteX, numCycles: 4, pageCycle: true, execute: withAddress((*CPU).SBC)},
+	0xFE: {Name: "INC", mode: absoluteX, numCycles: 7, execute: withAddress((*CPU).INC)},
+	0xFF: {Name: "ISC", mode: absoluteX, numCycles: 7, unofficial: true, execute: withAddress((*CPU).ISC)},
+}
+
+func init() {
+	for op := range instructions {
+		i := &instructions[op]
+		i.opcode = byte(op)
+		i.size = 1 + operandSize[i.mode]
+	}
+}
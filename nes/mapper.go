@@ -0,0 +1,170 @@
+package nes
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+//ErrUnsupportedMapper ... The ROM needs a mapper that isn't implemented
+var ErrUnsupportedMapper = errors.New("unsupported mapper")
+
+//Mapper ... Cartridge board hardware. Maps CPU accesses to $4020-$FFFF and PPU
+//accesses to $0000-$1FFF onto the cartridge's PRG and CHR memory.
+//Reference: https://wiki.nesdev.com/w/index.php/Mapper
+type Mapper interface {
+	cpuRead(addr uint16) byte
+	cpuWrite(addr uint16, val byte)
+	ppuRead(addr uint16) byte
+	ppuWrite(addr uint16, val byte)
+	mirroring() Mirroring
+	irq() bool //IRQ line, true while the mapper is asserting an interrupt
+	saveState(w io.Writer) error
+	loadState(r io.Reader) error
+}
+
+//cpuClocked ... Implemented by mappers that observe every CPU cycle (M2)
+type cpuClocked interface {
+	clockCPU()
+}
+
+//newMapper ... Creates the mapper named by the ROM header
+func newMapper(rom *ROM) (Mapper, error) {
+	switch rom.mapper {
+	case 0:
+		return newNROM(rom), nil
+	case 1:
+		m, err := newMMC1(rom)
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	case 2:
+		m, err := newUxROM(rom)
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	case 3:
+		return newCNROM(rom), nil
+	case 4:
+		m, err := newMMC3(rom)
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	case 7:
+		return newAxROM(rom), nil
+	}
+	return nil, fmt.Errorf("mapper: %w %d", ErrUnsupportedMapper, rom.mapper)
+}
+
+//board ... PRG and CHR memory common to every mapper
+type board struct {
+	prgROM []byte
+	prgRAM []byte
+	chr    []byte //CHR ROM, or CHR RAM when chrRAM is set
+	chrRAM bool
+	mirror Mirroring
+
+	busConflicts bool
+}
+
+func newBoard(rom *ROM) board {
+	b := board{
+		prgROM: rom.prgROM,
+		prgRAM: make([]byte, rom.prgRAMSize+rom.prgNVRAMSize),
+		chr:    rom.chrROM,
+		mirror: rom.mirroring,
+	}
+	if len(b.chr) == 0 {
+		size := rom.chrRAMSize + rom.chrNVRAMSize
+		if size == 0 {
+			size = chrUnitSize
+		}
+		b.chr = make([]byte, size)
+		b.chrRAM = true
+	}
+	// trainers are loaded into PRG RAM at $7000
+	if len(rom.trainerData) > 0 && len(b.prgRAM) >= 0x1000+trainerSize {
+		copy(b.prgRAM[0x1000:], rom.trainerData)
+	}
+	return b
+}
+
+//checkBankSizes ... Boards that bank PRG ROM or CHR memory need at least one
+//whole bank of it, smaller images can't be mapped
+func (b *board) checkBankSizes(name string, prgBank, chrBank int) error {
+	if len(b.prgROM) < prgBank {
+		return fmt.Errorf("mapper: %w: %s needs at least %dKB of PRG ROM, image has %d bytes",
+			ErrUnsupportedMapper, name, prgBank/kbSize, len(b.prgROM))
+	}
+	if len(b.chr) < chrBank {
+		return fmt.Errorf("mapper: %w: %s needs at least %dKB of CHR memory, image has %d bytes",
+			ErrUnsupportedMapper, name, chrBank/kbSize, len(b.chr))
+	}
+	return nil
+}
+
+//readPRGRAM ... Reads PRG RAM at $6000-$7FFF, returns 0 if the board has none
+func (b *board) readPRGRAM(addr uint16) byte {
+	if len(b.prgRAM) == 0 {
+		return 0
+	}
+	return b.prgRAM[int(addr-0x6000)%len(b.prgRAM)]
+}
+
+func (b *board) writePRGRAM(addr uint16, val byte) {
+	if len(b.prgRAM) == 0 {
+		return
+	}
+	b.prgRAM[int(addr-0x6000)%len(b.prgRAM)] = val
+}
+
+//hasBusConflicts ... Reports whether a discrete logic board should emulate bus
+//conflicts. NES 2.0 submapper 2 marks boards where the PRG ROM isn't disabled
+//during writes, submapper 1 marks boards without conflicts.
+func hasBusConflicts(rom *ROM) bool {
+	return rom.nes2 && rom.submapper == 2
+}
+
+//conflict ... Returns the value latched by a write to PRG ROM space. With bus
+//conflicts the ROM drives the data bus too, so the result is val AND the ROM byte.
+func (b *board) conflict(m Mapper, addr uint16, val byte) byte {
+	if b.busConflicts {
+		return val & m.cpuRead(addr)
+	}
+	return val
+}
+
+//boardState ... Serializable part of a board, ROM contents are not saved
+type boardState struct {
+	PRGRAM    []byte
+	CHRRAM    []byte
+	Mirroring Mirroring
+}
+
+func (b *board) state() boardState {
+	s := boardState{PRGRAM: b.prgRAM, Mirroring: b.mirror}
+	if b.chrRAM {
+		s.CHRRAM = b.chr
+	}
+	return s
+}
+
+func (b *board) restore(s boardState) {
+	copy(b.prgRAM, s.PRGRAM)
+	if b.chrRAM {
+		copy(b.chr, s.CHRRAM)
+	}
+	b.mirror = s.Mirroring
+}
+
+func encodeState(w io.Writer, state interface{}) error {
+	return gob.NewEncoder(w).Encode(state)
+}
+
+func decodeState(r io.Reader, state interface{}) error {
+	return gob.NewDecoder(r).Decode(state)
+}
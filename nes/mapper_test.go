@@ -0,0 +1,474 @@
+package nes
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+//TestNewMapperBankSizes ... Images smaller than one bank are rejected instead
+//of panicking while the banks are set up
+func TestNewMapperBankSizes(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		body   int
+		ok     bool
+	}{
+		{name: "MMC1 8KB PRG", header: inesHeader(0x34, 1, 0x10, 0x08, 0x00, 0x0F), body: 8*kbSize + chrUnitSize},
+		{name: "MMC1 2KB CHR RAM", header: inesHeader(1, 0, 0x10, 0x08, 0x00, 0x00, 0x00, 0x05), body: prgUnitSize},
+		{name: "MMC1 4KB CHR RAM", header: inesHeader(1, 0, 0x10, 0x08, 0x00, 0x00, 0x00, 0x06), body: prgUnitSize, ok: true},
+		{name: "UxROM 8KB PRG", header: inesHeader(0x34, 1, 0x20, 0x08, 0x00, 0x0F), body: 8*kbSize + chrUnitSize},
+		{name: "MMC3 8KB PRG", header: inesHeader(0x34, 1, 0x40, 0x08, 0x00, 0x0F), body: 8*kbSize + chrUnitSize, ok: true},
+		{name: "MMC3 512B CHR RAM", header: inesHeader(1, 0, 0x40, 0x08, 0x00, 0x00, 0x00, 0x03), body: prgUnitSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rom, err := loadImage(tt.header, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = newMapper(rom)
+			switch {
+			case tt.ok && err != nil:
+				t.Errorf("got %v, want no error", err)
+			case !tt.ok && !errors.Is(err, ErrUnsupportedMapper):
+				t.Errorf("got %v, want ErrUnsupportedMapper", err)
+			}
+		})
+	}
+}
+
+//newTestMMC3 ... MMC3 with 32KB PRG ROM and 8KB CHR ROM on a NES 2.0 submapper
+func newTestMMC3(t *testing.T, submapper byte) *MMC3 {
+	t.Helper()
+	rom, err := loadImage(inesHeader(2, 1, 0x40, 0x08, submapper<<4), 2*prgUnitSize+chrUnitSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := newMMC3(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+//a12Rise ... Holds PPU A12 low for a number of CPU cycles and raises it, the
+//way the pattern table switch between background and sprite fetches does
+func a12Rise(m *MMC3, lowCycles int) {
+	m.ppuRead(0x0FF0)
+	for i := 0; i < lowCycles; i++ {
+		m.clockCPU()
+	}
+	m.ppuRead(0x1000)
+}
+
+//TestMMC3IRQ ... The scanline counter is reloaded from the latch when it is
+//zero or after a $C001 write, and asserts IRQ when a clock leaves it at zero.
+//Each step is a register write, or a number of scanlines when lines is set,
+//followed by a check of the IRQ line.
+func TestMMC3IRQ(t *testing.T) {
+	type step struct {
+		addr  uint16
+		val   byte
+		lines int
+		irq   bool
+	}
+	tests := []struct {
+		name      string
+		submapper byte
+		steps     []step
+	}{
+		{
+			name: "counts down from the latch",
+			steps: []step{
+				{addr: 0xC000, val: 2}, {addr: 0xE001},
+				{lines: 2}, {lines: 1, irq: true},
+			},
+		},
+		{
+			name: "reloads from the latch at zero",
+			steps: []step{
+				{addr: 0xC000, val: 1}, {addr: 0xE001},
+				{lines: 1}, {lines: 1, irq: true},
+				{addr: 0xE000}, {addr: 0xE001},
+				{lines: 1}, {lines: 1, irq: true},
+			},
+		},
+		{
+			name: "$C001 reloads on the next clock",
+			steps: []step{
+				{addr: 0xC000, val: 3}, {addr: 0xE001},
+				{lines: 2}, {addr: 0xC001},
+				{lines: 1}, {lines: 2}, {lines: 1, irq: true},
+			},
+		},
+		{
+			name: "$E000 acknowledges and disables",
+			steps: []step{
+				{addr: 0xC000, val: 1}, {addr: 0xE001},
+				{lines: 2, irq: true}, {addr: 0xE000},
+				{lines: 2},
+			},
+		},
+		{
+			name: "disabled counter keeps counting",
+			steps: []step{
+				{addr: 0xC000, val: 2},
+				{lines: 2}, {addr: 0xE001}, {lines: 1, irq: true},
+			},
+		},
+		{
+			name: "latch 0 fires every scanline",
+			steps: []step{
+				{addr: 0xE001},
+				{lines: 1, irq: true}, {addr: 0xE000}, {addr: 0xE001},
+				{lines: 1, irq: true},
+			},
+		},
+		{
+			name:      "latch 0 fires once after $C001 on submapper 4",
+			submapper: 4,
+			steps: []step{
+				{addr: 0xE001}, {addr: 0xC001},
+				{lines: 1, irq: true}, {addr: 0xE000}, {addr: 0xE001},
+				{lines: 1},
+			},
+		},
+		{
+			name:      "counts down from the latch on submapper 4",
+			submapper: 4,
+			steps: []step{
+				{addr: 0xC000, val: 2}, {addr: 0xE001},
+				{lines: 2}, {lines: 1, irq: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMMC3(t, tt.submapper)
+			for i, s := range tt.steps {
+				if s.lines > 0 {
+					for j := 0; j < s.lines; j++ {
+						a12Rise(m, 8)
+					}
+				} else {
+					m.cpuWrite(s.addr, s.val)
+				}
+				if m.irq() != s.irq {
+					t.Fatalf("step %d: irq() = %v, want %v", i, m.irq(), s.irq)
+				}
+			}
+		})
+	}
+}
+
+//TestMMC3A12Filter ... Rising edges of A12 only clock the counter after A12
+//has been low for mmc3A12Filter CPU cycles
+func TestMMC3A12Filter(t *testing.T) {
+	for low := 0; low <= 4; low++ {
+		m := newTestMMC3(t, 0)
+		m.cpuWrite(0xE001, 0) //latch 0, every clock asserts IRQ
+		a12Rise(m, 8)
+		m.cpuWrite(0xE000, 0)
+		m.cpuWrite(0xE001, 0)
+
+		a12Rise(m, low)
+		if want := uint64(low) >= mmc3A12Filter; m.irq() != want {
+			t.Errorf("A12 low for %d cycles: irq() = %v, want %v", low, m.irq(), want)
+		}
+	}
+}
+
+//newTestMMC1 ... MMC1 with 128KB PRG ROM and 8KB CHR RAM
+func newTestMMC1(t *testing.T) *MMC1 {
+	t.Helper()
+	rom, err := loadImage(inesHeader(8, 0, 0x10), 8*prgUnitSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := newMMC1(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+//TestMMC1ShiftRegister ... Five writes load a register LSB first, the address
+//of the fifth picks the register, and a write with bit 7 set starts over
+func TestMMC1ShiftRegister(t *testing.T) {
+	tests := []struct {
+		name   string
+		addr   uint16
+		writes []byte
+		reg    func(m *MMC1) byte
+		want   byte
+	}{
+		{name: "control", addr: 0x8000, writes: []byte{1, 0, 0, 1, 0}, reg: func(m *MMC1) byte { return m.control }, want: 0x09},
+		{name: "CHR bank 0", addr: 0xA000, writes: []byte{0, 1, 0, 1, 1}, reg: func(m *MMC1) byte { return m.chrBank0 }, want: 0x1A},
+		{name: "CHR bank 1", addr: 0xDFFF, writes: []byte{1, 1, 0, 0, 0}, reg: func(m *MMC1) byte { return m.chrBank1 }, want: 0x03},
+		{name: "PRG bank", addr: 0xE000, writes: []byte{1, 0, 1, 1, 0}, reg: func(m *MMC1) byte { return m.prgBank }, want: 0x0D},
+		{name: "only bit 0 is shifted in", addr: 0xE000, writes: []byte{0x7D, 0x7E, 0x03, 0x41, 0x00}, reg: func(m *MMC1) byte { return m.prgBank }, want: 0x0D},
+		{name: "four writes load nothing", addr: 0xE000, writes: []byte{1, 1, 1, 1}, reg: func(m *MMC1) byte { return m.prgBank }, want: 0x00},
+		{name: "reset write starts over", addr: 0xE000, writes: []byte{1, 1, 0x80, 1, 0, 1, 1, 0}, reg: func(m *MMC1) byte { return m.prgBank }, want: 0x0D},
+		{name: "reset write sets PRG mode 3", addr: 0x8000, writes: []byte{0, 0, 0, 0, 0, 0x80}, reg: func(m *MMC1) byte { return m.control }, want: 0x0C},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMMC1(t)
+			m.control = 0
+			for _, val := range tt.writes {
+				m.cpuWrite(tt.addr, val)
+				m.clockCPU()
+				m.clockCPU()
+			}
+			if got := tt.reg(m); got != tt.want {
+				t.Errorf("register = $%02X, want $%02X", got, tt.want)
+			}
+		})
+	}
+}
+
+//clockedMapper ... RAM below $8000 and a mapper above, clocked on every access
+//like the cycle-stepped core does
+type clockedMapper struct {
+	flatMemory
+	mapper Mapper
+}
+
+func (b *clockedMapper) read(addr uint16) byte {
+	b.mapper.(cpuClocked).clockCPU()
+	if addr >= 0x8000 {
+		return b.mapper.cpuRead(addr)
+	}
+	return b.flatMemory[addr]
+}
+
+func (b *clockedMapper) write(addr uint16, val byte) {
+	b.mapper.(cpuClocked).clockCPU()
+	if addr >= 0x8000 {
+		b.mapper.cpuWrite(addr, val)
+		return
+	}
+	b.flatMemory[addr] = val
+}
+
+//TestMMC1ConsecutiveWrites ... INC $8000 writes the old value and then the new
+//one on the next cycle, MMC1 only registers the first
+func TestMMC1ConsecutiveWrites(t *testing.T) {
+	m := newTestMMC1(t)
+	bus := &clockedMapper{mapper: m}
+	copy(bus.flatMemory[0x0200:], []byte{0xEE, 0x00, 0x80}) //INC $8000
+	cpu := &CPU{bus: bus, PC: 0x0200}
+	if _, err := cpu.Step(); err != nil {
+		t.Fatal(err)
+	}
+	//$00 is shifted in, the write of $01 is ignored
+	if m.shift != mmc1ShiftReset>>1 {
+		t.Errorf("shift = $%02X, want $%02X", m.shift, mmc1ShiftReset>>1)
+	}
+}
+
+//TestMapperState ... Each mapper restores its registers, PRG RAM and CHR RAM
+//from a saved state into a freshly created mapper
+func TestMapperState(t *testing.T) {
+	type write struct {
+		addr uint16
+		val  byte
+	}
+	tests := []struct {
+		name   string
+		header []byte
+		body   int
+		writes []write
+	}{
+		{
+			name:   "NROM",
+			header: inesHeader(1, 0, 0x00),
+			body:   prgUnitSize,
+		},
+		{
+			name:   "MMC1",
+			header: inesHeader(8, 0, 0x10),
+			body:   8 * prgUnitSize,
+			writes: []write{
+				{0xE000, 1}, {0xE000, 1}, {0xE000, 0}, {0xE000, 0}, {0xE000, 0}, //PRG bank 3
+				{0xA000, 1}, {0xA000, 0}, //two bits of the next load
+			},
+		},
+		{
+			name:   "UxROM",
+			header: inesHeader(8, 0, 0x20),
+			body:   8 * prgUnitSize,
+			writes: []write{{0x8000, 5}},
+		},
+		{
+			name:   "CNROM",
+			header: inesHeader(2, 4, 0x30),
+			body:   2*prgUnitSize + 4*chrUnitSize,
+			writes: []write{{0x8000, 2}},
+		},
+		{
+			name:   "MMC3",
+			header: inesHeader(4, 2, 0x40),
+			body:   4*prgUnitSize + 2*chrUnitSize,
+			writes: []write{
+				{0x8000, 0xC6}, {0x8001, 3}, {0x8000, 0x02}, {0x8001, 9},
+				{0xA000, 1}, {0xC000, 5}, {0xC001, 0}, {0xE001, 0},
+			},
+		},
+		{
+			name:   "AxROM",
+			header: inesHeader(8, 0, 0x70),
+			body:   8 * prgUnitSize,
+			writes: []write{{0x8000, 0x13}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestMapper := func() Mapper {
+				rom, err := loadImage(tt.header, tt.body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				m, err := newMapper(rom)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return m
+			}
+			m := newTestMapper()
+			for _, w := range tt.writes {
+				if c, ok := m.(cpuClocked); ok {
+					c.clockCPU()
+					c.clockCPU()
+				}
+				m.cpuWrite(w.addr, w.val)
+			}
+			m.cpuWrite(0x6123, 0x42)
+			m.ppuWrite(0x0123, 0x99) //ignored by boards with CHR ROM
+			if mmc3, ok := m.(*MMC3); ok {
+				a12Rise(mmc3, 8)
+				a12Rise(mmc3, 8)
+			}
+
+			var buf bytes.Buffer
+			if err := m.saveState(&buf); err != nil {
+				t.Fatal(err)
+			}
+			restored := newTestMapper()
+			if err := restored.loadState(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(restored, m) {
+				t.Errorf("restored mapper\n%+v\nwant\n%+v", restored, m)
+			}
+		})
+	}
+}
+
+//TestBusConflicts ... On NES 2.0 submapper 2 boards the PRG ROM drives the data
+//bus during register writes too, so the register gets the value ANDed with the
+//ROM byte at the written address. Submapper 1 boards have no conflicts.
+func TestBusConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		body   int
+		reg    func(m Mapper) byte
+		want   byte
+	}{
+		{
+			name:   "UxROM",
+			header: inesHeader(2, 0, 0x20, 0x08, 0x20),
+			body:   2 * prgUnitSize,
+			reg:    func(m Mapper) byte { return m.(*UxROM).prgBank },
+			want:   0x06,
+		},
+		{
+			name:   "UxROM without conflicts",
+			header: inesHeader(2, 0, 0x20, 0x08, 0x10),
+			body:   2 * prgUnitSize,
+			reg:    func(m Mapper) byte { return m.(*UxROM).prgBank },
+			want:   0x07,
+		},
+		{
+			name:   "CNROM",
+			header: inesHeader(2, 4, 0x30, 0x08, 0x20),
+			body:   2*prgUnitSize + 4*chrUnitSize,
+			reg:    func(m Mapper) byte { return m.(*CNROM).chrBank },
+			want:   0x06,
+		},
+		{
+			name:   "CNROM without conflicts",
+			header: inesHeader(2, 4, 0x30, 0x08, 0x10),
+			body:   2*prgUnitSize + 4*chrUnitSize,
+			reg:    func(m Mapper) byte { return m.(*CNROM).chrBank },
+			want:   0x07,
+		},
+		{
+			name:   "AxROM",
+			header: inesHeader(2, 0, 0x70, 0x08, 0x20),
+			body:   2 * prgUnitSize,
+			reg:    func(m Mapper) byte { return m.(*AxROM).bank },
+			want:   0x06,
+		},
+		{
+			name:   "AxROM without conflicts",
+			header: inesHeader(2, 0, 0x70, 0x08, 0x10),
+			body:   2 * prgUnitSize,
+			reg:    func(m Mapper) byte { return m.(*AxROM).bank },
+			want:   0x07,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rom, err := loadImage(tt.header, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rom.prgROM[0] = 0x0E //the byte at $8000
+			m, err := newMapper(rom)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.cpuWrite(0x8000, 0x07)
+			if got := tt.reg(m); got != tt.want {
+				t.Errorf("register = $%02X, want $%02X", got, tt.want)
+			}
+		})
+	}
+}
+
+//TestAxROMMirroring ... Bit 4 of the bank register selects the nametable used
+//for all four logical ones
+func TestAxROMMirroring(t *testing.T) {
+	rom, err := loadImage(inesHeader(8, 0, 0x70), 8*prgUnitSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newAxROM(rom)
+	if m.mirroring() != MirrorSingleLower {
+		t.Errorf("mirroring at power on = %v, want MirrorSingleLower", m.mirroring())
+	}
+	for _, tt := range []struct {
+		val  byte
+		want Mirroring
+	}{
+		{0x10, MirrorSingleUpper},
+		{0x07, MirrorSingleLower},
+		{0x17, MirrorSingleUpper},
+		{0x00, MirrorSingleLower},
+	} {
+		m.cpuWrite(0x8000, tt.val)
+		if m.mirroring() != tt.want {
+			t.Errorf("after writing $%02X mirroring = %v, want %v", tt.val, m.mirroring(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package nes
+
+const ramSize uint16 = 0x0800
+
+//Memory ... Memory interface
+type Memory interface {
+	read(addr uint16) byte
+	write(addr uint16, val byte)
+}
+
+//RAM ... 2KB of internal work RAM, mirrored every 0x0800 bytes
+type RAM [ramSize]byte
+
+func (r *RAM) read(addr uint16) byte {
+	return r[addr%ramSize]
+}
+
+func (r *RAM) write(addr uint16, val byte) {
+	r[addr%ramSize] = val
+}
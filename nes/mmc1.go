@@ -0,0 +1,226 @@
+package nes
+
+import "io"
+
+//MMC1 ... Mapper 1, SxROM boards
+//Registers are loaded one bit at a time through a 5-bit serial shift register
+//Reference: https://wiki.nesdev.com/w/index.php/MMC1
+type MMC1 struct {
+	board
+	shift     byte //Serial load register, bit 4 marks the register as empty
+	control   byte //$8000-$9FFF: CPPMM, CHR mode, PRG mode, mirroring
+	chrBank0  byte //$A000-$BFFF
+	chrBank1  byte //$C000-$DFFF
+	prgBank   byte //$E000-$FFFF: RPPPP, PRG RAM disable and PRG bank
+	cycle     uint64
+	lastWrite uint64
+	written   bool
+	prgOffset [2]int //Offsets of the 16KB banks at $8000 and $C000
+	chrOffset [2]int //Offsets of the 4KB banks at $0000 and $1000
+}
+
+const mmc1ShiftReset byte = 0x10
+
+func newMMC1(rom *ROM) (*MMC1, error) {
+	m := &MMC1{
+		board:   newBoard(rom),
+		shift:   mmc1ShiftReset,
+		control: 0x0C,
+	}
+	if err := m.checkBankSizes("MMC1", prgUnitSize, 4*kbSize); err != nil {
+		return nil, err
+	}
+	m.updateBanks()
+	return m, nil
+}
+
+func (m *MMC1) cpuRead(addr uint16) byte {
+	switch {
+	case addr >= 0xC000:
+		return m.prgROM[m.prgOffset[1]+int(addr-0xC000)]
+	case addr >= 0x8000:
+		return m.prgROM[m.prgOffset[0]+int(addr-0x8000)]
+	case addr >= 0x6000 && m.prgRAMEnabled():
+		return m.readPRGRAM(addr)
+	}
+	return 0
+}
+
+func (m *MMC1) cpuWrite(addr uint16, val byte) {
+	switch {
+	case addr >= 0x8000:
+		m.writeShift(addr, val)
+	case addr >= 0x6000 && m.prgRAMEnabled():
+		m.writePRGRAM(addr, val)
+	}
+}
+
+//writeShift ... Feeds one bit into the serial load register. On the fifth write
+//the collected value is copied into the register selected by address bits 13-14.
+func (m *MMC1) writeShift(addr uint16, val byte) {
+	// The serial port ignores writes on the cycle right after another write,
+	// which makes read-modify-write instructions only register their first write
+	consecutive := m.written && m.cycle-m.lastWrite <= 1
+	m.written = true
+	m.lastWrite = m.cycle
+	if consecutive {
+		return
+	}
+
+	if hasBit(val, 7) {
+		m.shift = mmc1ShiftReset
+		m.control |= 0x0C
+		m.updateBanks()
+		return
+	}
+	full := hasBit(m.shift, 0)
+	m.shift = (m.shift >> 1) | ((val & 1) << 4)
+	if !full {
+		return
+	}
+
+	switch (addr >> 13) & 0x03 {
+	case 0:
+		m.control = m.shift
+	case 1:
+		m.chrBank0 = m.shift
+	case 2:
+		m.chrBank1 = m.shift
+	case 3:
+		m.prgBank = m.shift
+	}
+	m.shift = mmc1ShiftReset
+	m.updateBanks()
+}
+
+func (m *MMC1) prgRAMEnabled() bool {
+	return !hasBit(m.prgBank, 4)
+}
+
+//updateBanks ... Recomputes mirroring and bank offsets from the registers
+func (m *MMC1) updateBanks() {
+	switch m.control & 0x03 {
+	case 0:
+		m.mirror = MirrorSingleLower
+	case 1:
+		m.mirror = MirrorSingleUpper
+	case 2:
+		m.mirror = MirrorVertical
+	case 3:
+		m.mirror = MirrorHorizontal
+	}
+
+	if hasBit(m.control, 4) {
+		m.chrOffset[0] = m.chrBankOffset(int(m.chrBank0))
+		m.chrOffset[1] = m.chrBankOffset(int(m.chrBank1))
+	} else {
+		m.chrOffset[0] = m.chrBankOffset(int(m.chrBank0 &^ 1))
+		m.chrOffset[1] = m.chrBankOffset(int(m.chrBank0 | 1))
+	}
+
+	// SUROM and SXROM use CHR bank bit 4 to select the 256KB PRG outer bank
+	outer := 0
+	if len(m.prgROM) > 256*kbSize {
+		outer = int(m.chrBank0&0x10) * 16 * kbSize
+	}
+	bank := int(m.prgBank & 0x0F)
+	last := len(m.prgROM)/prgUnitSize - 1
+	if last > 0x0F {
+		last = 0x0F
+	}
+	switch (m.control >> 2) & 0x03 {
+	case 0, 1:
+		m.prgOffset[0] = outer + m.prgBankOffset(bank&^1)
+		m.prgOffset[1] = outer + m.prgBankOffset(bank|1)
+	case 2:
+		m.prgOffset[0] = outer
+		m.prgOffset[1] = outer + m.prgBankOffset(bank)
+	case 3:
+		m.prgOffset[0] = outer + m.prgBankOffset(bank)
+		m.prgOffset[1] = outer + m.prgBankOffset(last)
+	}
+}
+
+func (m *MMC1) prgBankOffset(bank int) int {
+	banks := len(m.prgROM) / prgUnitSize
+	if banks > 16 {
+		banks = 16
+	}
+	return (bank % banks) * prgUnitSize
+}
+
+func (m *MMC1) chrBankOffset(bank int) int {
+	banks := len(m.chr) / (4 * kbSize)
+	return (bank % banks) * 4 * kbSize
+}
+
+func (m *MMC1) ppuRead(addr uint16) byte {
+	addr &= 0x1FFF
+	return m.chr[m.chrOffset[addr/0x1000]+int(addr%0x1000)]
+}
+
+func (m *MMC1) ppuWrite(addr uint16, val byte) {
+	if m.chrRAM {
+		addr &= 0x1FFF
+		m.chr[m.chrOffset[addr/0x1000]+int(addr%0x1000)] = val
+	}
+}
+
+func (m *MMC1) mirroring() Mirroring {
+	return m.mirror
+}
+
+func (m *MMC1) irq() bool {
+	return false
+}
+
+//clockCPU ... Counts CPU cycles to detect writes on consecutive cycles
+func (m *MMC1) clockCPU() {
+	m.cycle++
+}
+
+type mmc1State struct {
+	Board    boardState
+	Shift    byte
+	Control  byte
+	CHRBank0 byte
+	CHRBank1 byte
+	PRGBank  byte
+
+	Cycle     uint64
+	LastWrite uint64
+	Written   bool
+}
+
+func (m *MMC1) saveState(w io.Writer) error {
+	return encodeState(w, mmc1State{
+		Board:    m.state(),
+		Shift:    m.shift,
+		Control:  m.control,
+		CHRBank0: m.chrBank0,
+		CHRBank1: m.chrBank1,
+		PRGBank:  m.prgBank,
+
+		Cycle:     m.cycle,
+		LastWrite: m.lastWrite,
+		Written:   m.written,
+	})
+}
+
+func (m *MMC1) loadState(r io.Reader) error {
+	var s mmc1State
+	if err := decodeState(r, &s); err != nil {
+		return err
+	}
+	m.restore(s.Board)
+	m.shift = s.Shift
+	m.control = s.Control
+	m.chrBank0 = s.CHRBank0
+	m.chrBank1 = s.CHRBank1
+	m.prgBank = s.PRGBank
+	m.cycle = s.Cycle
+	m.lastWrite = s.LastWrite
+	m.written = s.Written
+	m.updateBanks()
+	return nil
+}
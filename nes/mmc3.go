@@ -0,0 +1,253 @@
+package nes
+
+import "io"
+
+//MMC3 ... Mapper 4, TxROM boards
+//8KB PRG and 1KB/2KB CHR banking plus a scanline counter clocked by rising
+//edges of PPU address line A12, which raises an IRQ when it reaches zero.
+//Reference: https://wiki.nesdev.com/w/index.php/MMC3
+type MMC3 struct {
+	board
+	bankSelect byte    //$8000: CPxxxRRR, CHR A12 inversion, PRG mode, register index
+	registers  [8]byte //R0-R7 bank registers, written through $8001
+	prgRAMCtrl byte    //$A001: EWxxxxxx, PRG RAM enable and write protect
+	fourScreen bool
+
+	irqLatch   byte //$C000
+	irqCounter byte
+	irqReload  bool //Set by $C001, reloads the counter on the next clock
+	irqEnabled bool
+	irqPending bool
+	oldIRQ     bool //MMC3A behaviour, a counter reloaded with 0 only fires after a $C001 write
+
+	a12       bool   //Last seen level of PPU A12
+	a12Low    uint64 //CPU cycle at which A12 last went low
+	cycle     uint64
+	prgOffset [4]int //Offsets of the 8KB banks at $8000, $A000, $C000 and $E000
+	chrOffset [8]int //Offsets of the 1KB banks at $0000-$1FFF
+}
+
+const mmc3PRGBankSize int = 8 * kbSize
+const mmc3CHRBankSize int = kbSize
+
+//mmc3A12Filter ... A12 has to stay low for this many CPU cycles before a rising
+//edge clocks the counter, this filters out the sprite fetch pattern.
+const mmc3A12Filter uint64 = 3
+
+func newMMC3(rom *ROM) (*MMC3, error) {
+	m := &MMC3{
+		board:      newBoard(rom),
+		prgRAMCtrl: 0x80,
+		fourScreen: rom.mirroring == MirrorFourScreen,
+		oldIRQ:     rom.nes2 && rom.submapper == 4,
+	}
+	if err := m.checkBankSizes("MMC3", mmc3PRGBankSize, mmc3CHRBankSize); err != nil {
+		return nil, err
+	}
+	m.updateBanks()
+	return m, nil
+}
+
+func (m *MMC3) cpuRead(addr uint16) byte {
+	switch {
+	case addr >= 0x8000:
+		return m.prgROM[m.prgOffset[(addr-0x8000)/0x2000]+int(addr&0x1FFF)]
+	case addr >= 0x6000 && hasBit(m.prgRAMCtrl, 7):
+		return m.readPRGRAM(addr)
+	}
+	return 0
+}
+
+func (m *MMC3) cpuWrite(addr uint16, val byte) {
+	if addr < 0x6000 {
+		return
+	}
+	if addr < 0x8000 {
+		if hasBit(m.prgRAMCtrl, 7) && !hasBit(m.prgRAMCtrl, 6) {
+			m.writePRGRAM(addr, val)
+		}
+		return
+	}
+
+	even := addr&1 == 0
+	switch {
+	case addr < 0xA000 && even:
+		m.bankSelect = val
+		m.updateBanks()
+	case addr < 0xA000:
+		m.registers[m.bankSelect&0x07] = val
+		m.updateBanks()
+	case addr < 0xC000 && even:
+		if !m.fourScreen {
+			if hasBit(val, 0) {
+				m.mirror = MirrorHorizontal
+			} else {
+				m.mirror = MirrorVertical
+			}
+		}
+	case addr < 0xC000:
+		m.prgRAMCtrl = val
+	case addr < 0xE000 && even:
+		m.irqLatch = val
+	case addr < 0xE000:
+		m.irqCounter = 0
+		m.irqReload = true
+	case even:
+		m.irqEnabled = false
+		m.irqPending = false
+	default:
+		m.irqEnabled = true
+	}
+}
+
+//updateBanks ... Recomputes the bank offsets from the bank registers
+func (m *MMC3) updateBanks() {
+	prgBanks := len(m.prgROM) / mmc3PRGBankSize
+	prg := func(bank int) int {
+		return (bank % prgBanks) * mmc3PRGBankSize
+	}
+	secondLast := prg(prgBanks - 2)
+	if hasBit(m.bankSelect, 6) {
+		m.prgOffset[0] = secondLast
+		m.prgOffset[2] = prg(int(m.registers[6]))
+	} else {
+		m.prgOffset[0] = prg(int(m.registers[6]))
+		m.prgOffset[2] = secondLast
+	}
+	m.prgOffset[1] = prg(int(m.registers[7]))
+	m.prgOffset[3] = prg(prgBanks - 1)
+
+	chrBanks := len(m.chr) / mmc3CHRBankSize
+	chr := func(bank byte) int {
+		return (int(bank) % chrBanks) * mmc3CHRBankSize
+	}
+	// R0 and R1 select 2KB banks, the low bit is ignored
+	banks := [8]int{
+		chr(m.registers[0] &^ 1), chr(m.registers[0] | 1),
+		chr(m.registers[1] &^ 1), chr(m.registers[1] | 1),
+		chr(m.registers[2]), chr(m.registers[3]),
+		chr(m.registers[4]), chr(m.registers[5]),
+	}
+	// CHR A12 inversion swaps the 2KB and 1KB halves
+	for i := range banks {
+		if hasBit(m.bankSelect, 7) {
+			m.chrOffset[i^4] = banks[i]
+		} else {
+			m.chrOffset[i] = banks[i]
+		}
+	}
+}
+
+func (m *MMC3) ppuRead(addr uint16) byte {
+	m.watchA12(addr)
+	addr &= 0x1FFF
+	return m.chr[m.chrOffset[addr/0x0400]+int(addr%0x0400)]
+}
+
+func (m *MMC3) ppuWrite(addr uint16, val byte) {
+	m.watchA12(addr)
+	if m.chrRAM {
+		addr &= 0x1FFF
+		m.chr[m.chrOffset[addr/0x0400]+int(addr%0x0400)] = val
+	}
+}
+
+//watchA12 ... Clocks the scanline counter on filtered rising edges of PPU A12
+func (m *MMC3) watchA12(addr uint16) {
+	a12 := hasBit(byte(addr>>8), 4)
+	switch {
+	case a12 && !m.a12:
+		if m.cycle-m.a12Low >= mmc3A12Filter {
+			m.clockScanline()
+		}
+	case !a12 && m.a12:
+		m.a12Low = m.cycle
+	}
+	m.a12 = a12
+}
+
+func (m *MMC3) clockScanline() {
+	previous := m.irqCounter
+	reload := m.irqReload
+	if m.irqCounter == 0 || m.irqReload {
+		m.irqCounter = m.irqLatch
+	} else {
+		m.irqCounter--
+	}
+	m.irqReload = false
+
+	if m.irqCounter != 0 || !m.irqEnabled {
+		return
+	}
+	if !m.oldIRQ || previous > 0 || reload {
+		m.irqPending = true
+	}
+}
+
+func (m *MMC3) mirroring() Mirroring {
+	return m.mirror
+}
+
+func (m *MMC3) irq() bool {
+	return m.irqPending
+}
+
+//clockCPU ... Counts CPU cycles for the A12 low time filter
+func (m *MMC3) clockCPU() {
+	m.cycle++
+}
+
+type mmc3State struct {
+	Board      boardState
+	BankSelect byte
+	Registers  [8]byte
+	PRGRAMCtrl byte
+	IRQLatch   byte
+	IRQCounter byte
+	IRQReload  bool
+	IRQEnabled bool
+	IRQPending bool
+
+	A12    bool
+	A12Low uint64
+	Cycle  uint64
+}
+
+func (m *MMC3) saveState(w io.Writer) error {
+	return encodeState(w, mmc3State{
+		Board:      m.state(),
+		BankSelect: m.bankSelect,
+		Registers:  m.registers,
+		PRGRAMCtrl: m.prgRAMCtrl,
+		IRQLatch:   m.irqLatch,
+		IRQCounter: m.irqCounter,
+		IRQReload:  m.irqReload,
+		IRQEnabled: m.irqEnabled,
+		IRQPending: m.irqPending,
+
+		A12:    m.a12,
+		A12Low: m.a12Low,
+		Cycle:  m.cycle,
+	})
+}
+
+func (m *MMC3) loadState(r io.Reader) error {
+	var s mmc3State
+	if err := decodeState(r, &s); err != nil {
+		return err
+	}
+	m.restore(s.Board)
+	m.bankSelect = s.BankSelect
+	m.registers = s.Registers
+	m.prgRAMCtrl = s.PRGRAMCtrl
+	m.irqLatch = s.IRQLatch
+	m.irqCounter = s.IRQCounter
+	m.irqReload = s.IRQReload
+	m.irqEnabled = s.IRQEnabled
+	m.irqPending = s.IRQPending
+	m.a12 = s.A12
+	m.a12Low = s.A12Low
+	m.cycle = s.Cycle
+	m.updateBanks()
+	return nil
+}
@@ -0,0 +1,299 @@
+package nes
+
+import (
+	"errors"
+	"fmt"
+)
+
+//Options ... Configures a console created with New
+type Options struct {
+	SampleRate float64     //Audio output rate in Hz, 0 selects 44100
+	StartPC    uint16      //Overrides the RESET vector when non-zero, e.g. $C000 for nestest
+	Input      InputSource //Controller state, both controllers idle if nil
+	Tracer     *Tracer     //Logs every instruction executed, off if nil
+
+	//CycleStepped selects the cycle-stepped CPU core, which makes one bus
+	//access per cycle so the rest of the console sees accesses in the middle
+	//of an instruction at the right time. It is slower than the default core.
+	CycleStepped bool
+}
+
+//NES ... A Nintendo Entertainment System with a cartridge inserted
+type NES struct {
+	cpu     CPU
+	ppu     *PPU
+	apu     *APU
+	bus     *Bus
+	mapper  Mapper
+	rom     ROM
+	startPC uint16 //Overrides the RESET vector when non-zero, e.g. $C000 for nestest
+
+	sampleRate float64 //Audio output rate in Hz, defaults to defaultSampleRate
+
+	input       InputSource
+	polledFrame uint64
+
+	core           *cycleCore //Set when running the cycle-stepped core
+	midInstruction bool       //Tick left the cycle-stepped core inside an instruction
+	owedCycles     int        //Cycles of the last instruction not yet ticked
+	closed         bool
+}
+
+const defaultSampleRate = 44100
+
+//ErrClosed ... Returned by the methods that run a console after Close
+var ErrClosed = errors.New("nes: console is closed")
+
+//New ... Creates a console from an iNES or NES 2.0 ROM image and powers it on
+func New(romData []byte, opts Options) (*NES, error) {
+	nes := &NES{
+		rom:        ROM{data: romData},
+		startPC:    opts.StartPC,
+		sampleRate: opts.SampleRate,
+		input:      opts.Input,
+	}
+	if err := nes.powerOn(); err != nil {
+		return nil, err
+	}
+	if opts.CycleStepped {
+		nes.core = newCycleCore(&nes.cpu, nes.bus, nes.beginInstruction)
+	}
+	nes.SetTracer(opts.Tracer)
+	return nes, nil
+}
+
+//Close ... Releases the coroutine the cycle-stepped core runs the CPU on. A
+//cycle-stepped console that is no longer needed has to be closed, or the
+//coroutine is never freed. Closed consoles return ErrClosed instead of running.
+func (nes *NES) Close() {
+	if nes.closed {
+		return
+	}
+	nes.closed = true
+	if nes.core != nil {
+		nes.core.detach()
+	}
+}
+
+//CPU ... The console's CPU, for inspecting registers and cycle count
+func (nes *NES) CPU() *CPU {
+	return &nes.cpu
+}
+
+//PPU ... The console's PPU, for inspecting the scanline, dot and frame count
+func (nes *NES) PPU() *PPU {
+	return nes.ppu
+}
+
+//powerOn ... Cold boot, RAM is cleared and the CPU runs its RESET sequence
+func (nes *NES) powerOn() error {
+	// initialize stuff
+	if err := nes.rom.load(); err != nil {
+		return fmt.Errorf("nes: loading ROM: %w", err)
+	}
+	mapper, err := newMapper(&nes.rom)
+	if err != nil {
+		return fmt.Errorf("nes: loading ROM: %w", err)
+	}
+	nes.mapper = mapper
+	nes.ppu = newPPU(nes.mapper)
+	nes.bus = newBus(nes.ppu, nes.mapper)
+	nes.bus.cpu = &nes.cpu
+	if nes.sampleRate == 0 {
+		nes.sampleRate = defaultSampleRate
+	}
+	nes.apu = newAPU(nes.bus, &nes.cpu, nes.sampleRate)
+	nes.bus.apu = nes.apu
+	nes.cpu.init(nes.bus)
+	nes.clockReset()
+	nes.forceStart()
+	// the first frame's input is latched up front, later frames as they begin
+	nes.pollInput()
+	return nil
+}
+
+//Reset ... Soft reset, as if the console's reset button was pressed.
+//Unlike powerOn, RAM and mapper state survive.
+func (nes *NES) Reset() {
+	nes.apu.write(apuStatus, 0) // reset silences all channels
+	nes.owedCycles = 0
+	nes.midInstruction = false
+	if nes.core != nil && !nes.closed {
+		nes.core.detach()
+		defer nes.core.attach()
+	}
+	nes.ppu.reset()
+	nes.cpu.reset()
+	nes.clockReset()
+	nes.forceStart()
+}
+
+//clockReset ... The PPU and APU keep running through the 7 cycles of the CPU's
+//RESET sequence, so the first instruction starts at PPU dot 21
+func (nes *NES) clockReset() {
+	nes.owedCycles = 7
+	nes.clockOwed()
+}
+
+//SetInputSource ... Connects the source polled for controller state each frame
+func (nes *NES) SetInputSource(input InputSource) {
+	nes.input = input
+}
+
+//pollInput ... Latches the state of both controllers for a new frame
+func (nes *NES) pollInput() {
+	nes.polledFrame = nes.ppu.Frame
+	if nes.input == nil {
+		return
+	}
+	for port := range nes.bus.controllers {
+		nes.bus.controllers[port].buttons = nes.input.Buttons(port, nes.ppu.Frame)
+	}
+}
+
+func (nes *NES) forceStart() {
+	if nes.startPC != 0 {
+		nes.cpu.PC = nes.startPC
+	}
+}
+
+//RunFrame ... Runs until the PPU enters VBlank and returns the completed frame
+//along with the audio samples produced meanwhile. The frame is only valid until
+//the next call. A CPU fault stops the frame early and is returned.
+func (nes *NES) RunFrame() (*Frame, []float32, error) {
+	nes.ppu.frameReady = false
+	for !nes.ppu.frameReady {
+		if _, err := nes.step(); err != nil {
+			return nes.ppu.FrameBuffer(), nes.apu.drainSamples(), err
+		}
+	}
+	return nes.ppu.FrameBuffer(), nes.apu.drainSamples(), nil
+}
+
+//StepInstruction ... Executes one CPU instruction, or one cycle of a DMA stall,
+//and returns the number of CPU cycles that elapsed. After Tick it finishes the
+//instruction in progress.
+func (nes *NES) StepInstruction() (int, error) {
+	return nes.step()
+}
+
+//StepCycles ... Runs whole instructions until at least n CPU cycles have
+//elapsed and returns the number actually run
+func (nes *NES) StepCycles(n int) (int, error) {
+	cycles := 0
+	for cycles < n {
+		c, err := nes.step()
+		cycles += c
+		if err != nil {
+			return cycles, err
+		}
+	}
+	return cycles, nil
+}
+
+//AudioSamples ... Returns the audio samples produced since the last call, for
+//callers driving the emulator with StepInstruction or StepCycles
+func (nes *NES) AudioSamples() []float32 {
+	return nes.apu.drainSamples()
+}
+
+//Tick ... Advances the console by one CPU cycle. The cycle-stepped core makes
+//exactly one bus access per Tick for the CPU or OAM DMA, or none on the halt
+//and alignment cycles of a DMA stall. DMC sample fetches are made by the APU
+//as it is clocked, on top of that access.
+//The instruction-stepped core runs a whole instruction on its first cycle and
+//only clocks the rest of the console on the others.
+func (nes *NES) Tick() error {
+	if nes.closed {
+		return ErrClosed
+	}
+	if nes.core != nil {
+		ev := nes.core.tick()
+		nes.clock()
+		nes.midInstruction = !ev.done
+		return ev.err
+	}
+	if nes.owedCycles == 0 {
+		nes.beginInstruction()
+		cycles, err := nes.cpu.Step()
+		if err != nil {
+			return err
+		}
+		nes.owedCycles = cycles
+	}
+	nes.clockOwedCycle()
+	return nil
+}
+
+//step ... Executes one CPU instruction and clocks the rest of the console for
+//the cycles it took
+func (nes *NES) step() (int, error) {
+	if nes.closed {
+		return 0, ErrClosed
+	}
+	if nes.core != nil {
+		cycles := 0
+		for {
+			ev := nes.core.tick()
+			nes.clock()
+			cycles++
+			if ev.done {
+				nes.midInstruction = false
+				return cycles, ev.err
+			}
+		}
+	}
+	if nes.owedCycles > 0 {
+		return nes.clockOwed(), nil
+	}
+	nes.beginInstruction()
+	cycles, err := nes.cpu.Step()
+	nes.owedCycles = cycles
+	nes.clockOwed()
+	return cycles, err
+}
+
+//clockOwed ... Clocks the rest of the console through the cycles the CPU has
+//run ahead and returns how many there were
+func (nes *NES) clockOwed() int {
+	cycles := nes.owedCycles
+	for nes.owedCycles > 0 {
+		nes.clockOwedCycle()
+	}
+	return cycles
+}
+
+//clockOwedCycle ... Clocks one cycle the CPU has run ahead. The CPU's interrupt
+//poll happens before the last one, as it does on its second to last cycle.
+func (nes *NES) clockOwedCycle() {
+	nes.owedCycles--
+	if nes.owedCycles == 0 {
+		nes.cpu.pollInterrupts()
+	}
+	nes.clock()
+}
+
+//beginInstruction ... Runs before each instruction, latching new controller
+//state once a frame has passed
+func (nes *NES) beginInstruction() {
+	if nes.ppu.Frame != nes.polledFrame {
+		nes.pollInput()
+	}
+}
+
+//clock ... Advances everything but the CPU by one CPU cycle, the PPU runs
+//three dots per CPU cycle and the APU one
+func (nes *NES) clock() {
+	if m, ok := nes.mapper.(cpuClocked); ok {
+		m.clockCPU()
+	}
+	for i := 0; i < 3; i++ {
+		nes.ppu.step()
+	}
+	nes.apu.step()
+	frameIRQ, dmcIRQ := nes.apu.irq()
+	nes.cpu.setNMI(nes.ppu.nmi())
+	nes.cpu.setIRQ(irqMapper, nes.mapper.irq())
+	nes.cpu.setIRQ(irqFrameCounter, frameIRQ)
+	nes.cpu.setIRQ(irqDMC, dmcIRQ)
+}
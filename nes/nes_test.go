@@ -0,0 +1,51 @@
+package nes
+
+import "testing"
+
+//TestReset ... Reset jumps through $FFFC with SP dropped by 3 and I set, and
+//clears the PPU registers, while RAM keeps its contents
+func TestReset(t *testing.T) {
+	prg := []byte{
+		0x58,       //CLI
+		0xA9, 0x42, //LDA #$42
+		0x8D, 0x00, 0x03, //STA $0300
+		0xA9, 0x80, //LDA #$80
+		0x8D, 0x00, 0x20, //STA $2000
+		0x8D, 0x05, 0x20, //STA $2005
+		0x4C, 0x0E, 0xC0, //JMP $C00E
+	}
+	for _, cycleStepped := range []bool{false, true} {
+		console, err := New(nromImage(prg, 0xC000), Options{CycleStepped: cycleStepped})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer console.Close()
+		for i := 0; i < 7; i++ {
+			if _, err := console.StepInstruction(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		cpu := console.CPU()
+		sp := cpu.SP
+		if cpu.PC != 0xC00E || hasBit(cpu.P, 2) {
+			t.Fatalf("PC = $%04X, P = $%02X before reset", cpu.PC, cpu.P)
+		}
+
+		console.Reset()
+		if cpu.PC != 0xC000 {
+			t.Errorf("PC = $%04X, want $C000", cpu.PC)
+		}
+		if cpu.SP != sp-3 {
+			t.Errorf("SP = $%02X, want $%02X", cpu.SP, sp-3)
+		}
+		if !hasBit(cpu.P, 2) {
+			t.Error("I flag clear")
+		}
+		if ram := console.bus.read(0x0300); ram != 0x42 {
+			t.Errorf("$0300 = $%02X, want $42", ram)
+		}
+		if ppu := console.ppu; ppu.ctrl != 0 || ppu.w || ppu.t != 0 {
+			t.Errorf("PPUCTRL = $%02X, w = %v, t = $%04X after reset", ppu.ctrl, ppu.w, ppu.t)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package nes
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+//The nestest ROM and its golden log aren't distributed with the source. Drop
+//nestest.nes and nestest.log into testdata, or point NESTEST_ROM and
+//NESTEST_LOG at them, and the test runs; otherwise it is skipped.
+//Reference: https://wiki.nesdev.com/w/index.php/Emulator_tests
+const (
+	nestestROM = "testdata/nestest.nes"
+	nestestLog = "testdata/nestest.log"
+)
+
+//traceState ... The machine state fields of one trace line
+type traceState struct {
+	line                       string
+	pc                         string
+	a, x, y, p, sp, ppu, cycle string
+}
+
+var traceFields = regexp.MustCompile(`A:(\w\w) X:(\w\w) Y:(\w\w) P:(\w\w) SP:(\w\w)(?: PPU:\s*(\d+,\s*\d+))?.*?CYC:\s*(\d+)`)
+
+func parseTrace(line string) (traceState, error) {
+	m := traceFields.FindStringSubmatch(line)
+	if len(line) < 4 || m == nil {
+		return traceState{}, fmt.Errorf("malformed trace line %q", line)
+	}
+	return traceState{
+		line:  line,
+		pc:    line[:4],
+		a:     m[1],
+		x:     m[2],
+		y:     m[3],
+		p:     m[4],
+		sp:    m[5],
+		ppu:   strings.Join(strings.Fields(m[6]), ""),
+		cycle: m[7],
+	}, nil
+}
+
+//diff ... Register level differences between the expected and actual state.
+//PPU position is only compared when the reference log has it.
+func (want traceState) diff(got traceState) []string {
+	var d []string
+	check := func(name, w, g string) {
+		if w != g {
+			d = append(d, fmt.Sprintf("%s: want %s, got %s", name, w, g))
+		}
+	}
+	check("PC", want.pc, got.pc)
+	check("A", want.a, got.a)
+	check("X", want.x, got.x)
+	check("Y", want.y, got.y)
+	check("P", want.p, got.p)
+	check("SP", want.sp, got.sp)
+	if want.ppu != "" {
+		check("PPU", want.ppu, got.ppu)
+	}
+	check("CYC", want.cycle, got.cycle)
+	return d
+}
+
+//lineWriter ... Collects the tracer's output, one write per instruction
+type lineWriter struct {
+	lines []string
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.lines = append(w.lines, strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
+
+func testdataPath(t *testing.T, env, fallback string) string {
+	path := os.Getenv(env)
+	if path == "" {
+		path = fallback
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("%s not found, set %s to run", path, env)
+	}
+	return path
+}
+
+func readTrace(t *testing.T, path string) []traceState {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var states []traceState
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r ")
+		if line == "" {
+			continue
+		}
+		s, err := parseTrace(line)
+		if err != nil {
+			t.Fatalf("%s:%d: %v", path, len(states)+1, err)
+		}
+		states = append(states, s)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return states
+}
+
+//TestNestest ... Runs nestest in automated mode from $C000 on both CPU cores
+//and compares every instruction against the golden log, then checks the result
+//codes it leaves in $02 (official opcodes) and $03 (unofficial opcodes), zero
+//meaning pass.
+func TestNestest(t *testing.T) {
+	romPath := testdataPath(t, "NESTEST_ROM", nestestROM)
+	logPath := testdataPath(t, "NESTEST_LOG", nestestLog)
+
+	want := readTrace(t, logPath)
+	rom, err := os.ReadFile(romPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Run("InstructionStepped", func(t *testing.T) { runNestest(t, rom, want, false) })
+	t.Run("CycleStepped", func(t *testing.T) { runNestest(t, rom, want, true) })
+}
+
+func runNestest(t *testing.T, rom []byte, want []traceState, cycleStepped bool) {
+	out := &lineWriter{}
+	console, err := New(rom, Options{StartPC: 0xC000, Tracer: NewTracer(out), CycleStepped: cycleStepped})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for checked := 0; checked < len(want); {
+		_, err := console.StepInstruction()
+		for ; checked < len(out.lines) && checked < len(want); checked++ {
+			got, perr := parseTrace(out.lines[checked])
+			if perr != nil {
+				t.Fatal(perr)
+			}
+			if d := want[checked].diff(got); d != nil {
+				t.Fatalf("diverged at line %d\nwant: %s\ngot:  %s\n%s",
+					checked+1, want[checked].line, got.line, strings.Join(d, "\n"))
+			}
+		}
+		if err != nil {
+			t.Fatalf("after line %d: %v", checked, err)
+		}
+		if console.CPU().Halted() {
+			t.Fatalf("CPU halted after line %d", checked)
+		}
+	}
+
+	if r := console.bus.peek(0x0002); r != 0 {
+		t.Errorf("official opcode tests failed, $02 = $%02X", r)
+	}
+	if r := console.bus.peek(0x0003); r != 0 {
+		t.Errorf("unofficial opcode tests failed, $03 = $%02X", r)
+	}
+}
@@ -0,0 +1,62 @@
+package nes
+
+import "io"
+
+//NROM ... Mapper 0, no bank switching
+//NROM-128 has 16KB of PRG ROM mirrored into $C000, NROM-256 has 32KB
+//Reference: https://wiki.nesdev.com/w/index.php/NROM
+type NROM struct {
+	board
+}
+
+func newNROM(rom *ROM) *NROM {
+	return &NROM{board: newBoard(rom)}
+}
+
+func (m *NROM) cpuRead(addr uint16) byte {
+	switch {
+	case addr >= 0x8000:
+		return m.prgROM[int(addr-0x8000)%len(m.prgROM)]
+	case addr >= 0x6000:
+		return m.readPRGRAM(addr)
+	}
+	return 0
+}
+
+func (m *NROM) cpuWrite(addr uint16, val byte) {
+	// PRG ROM is write protected
+	if addr >= 0x6000 && addr < 0x8000 {
+		m.writePRGRAM(addr, val)
+	}
+}
+
+func (m *NROM) ppuRead(addr uint16) byte {
+	return m.chr[int(addr)%len(m.chr)]
+}
+
+func (m *NROM) ppuWrite(addr uint16, val byte) {
+	if m.chrRAM {
+		m.chr[int(addr)%len(m.chr)] = val
+	}
+}
+
+func (m *NROM) mirroring() Mirroring {
+	return m.mirror
+}
+
+func (m *NROM) irq() bool {
+	return false
+}
+
+func (m *NROM) saveState(w io.Writer) error {
+	return encodeState(w, m.state())
+}
+
+func (m *NROM) loadState(r io.Reader) error {
+	var s boardState
+	if err := decodeState(r, &s); err != nil {
+		return err
+	}
+	m.restore(s)
+	return nil
+}
@@ -0,0 +1,704 @@
+package nes
+
+//Screen dimensions
+const (
+	ScreenWidth  = 256
+	ScreenHeight = 240
+)
+
+//PPU scanline layout, NTSC
+const (
+	dotsPerLine      = 341
+	vblankLine       = 241
+	preRenderLine    = 261
+	visibleLines     = 240
+	maxLineSprites   = 8
+	patternTableSize = 0x1000
+)
+
+//Frame ... A frame of palette indices (0-63), row major, ScreenWidth x ScreenHeight
+type Frame [ScreenWidth * ScreenHeight]byte
+
+//PPU ... Represents the Ricoh 2C02 Picture Processing Unit
+//Reference: https://wiki.nesdev.com/w/index.php/PPU
+type PPU struct {
+	Cycle    int    //Dot within the scanline, 0-340
+	ScanLine int    //0-239 visible, 240 post-render, 241-260 VBlank, 261 pre-render
+	Frame    uint64 //Frames since power on
+	oddFrame bool
+
+	mapper     Mapper
+	nametables [0x1000]byte //2KB CIRAM, the rest is only used by four-screen boards
+	palette    [32]byte
+	oam        [256]byte
+
+	// $2000-$2007
+	ctrl       byte //PPUCTRL
+	mask       byte //PPUMASK
+	status     byte //PPUSTATUS
+	oamAddr    byte //OAMADDR
+	readBuffer byte //PPUDATA read buffer
+	latch      byte //I/O latch, returned for reads of write-only registers
+
+	// Loopy scroll registers
+	//Reference: https://wiki.nesdev.com/w/index.php/PPU_scrolling
+	v uint16 //Current VRAM address
+	t uint16 //Temporary VRAM address, the top left onscreen tile
+	x byte   //Fine X scroll
+	w bool   //First/second write toggle for $2005 and $2006
+
+	suppressVBlank bool //$2002 was read the dot before VBlank is set
+	frameReady     bool //Set when VBlank starts and the front buffer holds a new frame
+
+	// Background pipeline
+	ntByte      byte
+	atBits      byte
+	tileLo      byte
+	tileHi      byte
+	bgShiftLo   uint16
+	bgShiftHi   uint16
+	attrShiftLo uint16
+	attrShiftHi uint16
+
+	// Sprites found by evaluation for the next scanline
+	spriteCount    int
+	spriteIndex    [maxLineSprites]byte
+	spritePatterns [maxLineSprites][2]byte
+	spriteX        [maxLineSprites]byte
+	spriteAttr     [maxLineSprites]byte
+
+	front *Frame //Last completed frame
+	back  *Frame //Frame being drawn
+}
+
+func newPPU(mapper Mapper) *PPU {
+	return &PPU{
+		mapper: mapper,
+		front:  &Frame{},
+		back:   &Frame{},
+	}
+}
+
+//reset ... State the 2C02 clears on RESET. PPUCTRL, PPUMASK, the scroll and
+//the write toggle are cleared, OAMADDR, PPUADDR and the memories are kept.
+//Reference: https://wiki.nesdev.com/w/index.php/PPU_power_up_state
+func (ppu *PPU) reset() {
+	ppu.ctrl = 0
+	ppu.mask = 0
+	ppu.readBuffer = 0
+	ppu.t = 0
+	ppu.x = 0
+	ppu.w = false
+	ppu.oddFrame = false
+}
+
+//FrameBuffer ... The most recently completed frame
+func (ppu *PPU) FrameBuffer() *Frame {
+	return ppu.front
+}
+
+//nmi ... NMI output, asserted during VBlank while NMI generation is enabled
+func (ppu *PPU) nmi() bool {
+	return hasBit(ppu.ctrl, 7) && hasBit(ppu.status, 7)
+}
+
+func (ppu *PPU) renderingEnabled() bool {
+	return ppu.mask&0x18 != 0
+}
+
+/*
+===============================================================================
+				CPU Registers ($2000-$2007)
+===============================================================================
+*/
+
+func (ppu *PPU) read(addr uint16) byte {
+	switch addr % 8 {
+	case 2:
+		ppu.latch = (ppu.status & 0xE0) | (ppu.latch & 0x1F)
+		ppu.status = clearBit(ppu.status, 7)
+		ppu.w = false
+		// reading one dot before the flag is set means it never gets set
+		if ppu.ScanLine == vblankLine && ppu.Cycle == 1 {
+			ppu.suppressVBlank = true
+		}
+	case 4:
+		ppu.latch = ppu.oam[ppu.oamAddr]
+	case 7:
+		addr := ppu.v & 0x3FFF
+		if addr >= 0x3F00 {
+			// palette reads aren't buffered, the buffer gets the nametable underneath
+			ppu.latch = (ppu.latch & 0xC0) | (ppu.readVRAM(addr) & 0x3F)
+			ppu.readBuffer = ppu.readVRAM(addr - 0x1000)
+		} else {
+			ppu.latch = ppu.readBuffer
+			ppu.readBuffer = ppu.readVRAM(addr)
+		}
+		ppu.incrementAddress()
+	}
+	return ppu.latch
+}
+
+func (ppu *PPU) write(addr uint16, val byte) {
+	ppu.latch = val
+	switch addr % 8 {
+	case 0:
+		ppu.ctrl = val
+		ppu.t = (ppu.t & 0xF3FF) | (uint16(val&0x03) << 10)
+	case 1:
+		ppu.mask = val
+	case 3:
+		ppu.oamAddr = val
+	case 4:
+		ppu.oam[ppu.oamAddr] = val
+		ppu.oamAddr++
+	case 5:
+		if !ppu.w {
+			ppu.t = (ppu.t & 0xFFE0) | uint16(val>>3)
+			ppu.x = val & 0x07
+		} else {
+			ppu.t = (ppu.t & 0x8FFF) | (uint16(val&0x07) << 12)
+			ppu.t = (ppu.t & 0xFC1F) | (uint16(val&0xF8) << 2)
+		}
+		ppu.w = !ppu.w
+	case 6:
+		if !ppu.w {
+			ppu.t = (ppu.t & 0x80FF) | (uint16(val&0x3F) << 8)
+		} else {
+			ppu.t = (ppu.t & 0xFF00) | uint16(val)
+			ppu.v = ppu.t
+		}
+		ppu.w = !ppu.w
+	case 7:
+		ppu.writeVRAM(ppu.v&0x3FFF, val)
+		ppu.incrementAddress()
+	}
+}
+
+//incrementAddress ... Advances v after a $2007 access. While rendering the
+//increment is replaced by the coarse X and Y increments of the fetch pipeline.
+func (ppu *PPU) incrementAddress() {
+	if ppu.renderingEnabled() && (ppu.ScanLine < visibleLines || ppu.ScanLine == preRenderLine) {
+		ppu.incrementX()
+		ppu.incrementY()
+		return
+	}
+	if hasBit(ppu.ctrl, 2) {
+		ppu.v += 32
+	} else {
+		ppu.v++
+	}
+}
+
+/*
+===============================================================================
+				PPU Memory Map
+===============================================================================
+*/
+
+//Reference: https://wiki.nesdev.com/w/index.php/PPU_memory_map
+
+//nametableLayout ... Physical nametable used for each of the four logical ones
+var nametableLayout = [...][4]uint16{
+	MirrorHorizontal:  {0, 0, 1, 1},
+	MirrorVertical:    {0, 1, 0, 1},
+	MirrorSingleLower: {0, 0, 0, 0},
+	MirrorSingleUpper: {1, 1, 1, 1},
+	MirrorFourScreen:  {0, 1, 2, 3},
+}
+
+func (ppu *PPU) nametableAddress(addr uint16) uint16 {
+	addr = (addr - 0x2000) % 0x1000
+	table := nametableLayout[ppu.mapper.mirroring()][addr/0x0400]
+	return table*0x0400 + addr%0x0400
+}
+
+//paletteAddress ... $3F10, $3F14, $3F18 and $3F1C mirror the backdrop entries
+func paletteAddress(addr uint16) uint16 {
+	addr %= 32
+	if addr >= 16 && addr%4 == 0 {
+		addr -= 16
+	}
+	return addr
+}
+
+func (ppu *PPU) readVRAM(addr uint16) byte {
+	addr &= 0x3FFF
+	switch {
+	case addr < 0x2000:
+		return ppu.mapper.ppuRead(addr)
+	case addr < 0x3F00:
+		return ppu.nametables[ppu.nametableAddress(addr)]
+	default:
+		val := ppu.palette[paletteAddress(addr)]
+		if hasBit(ppu.mask, 0) { //greyscale
+			val &= 0x30
+		}
+		return val
+	}
+}
+
+func (ppu *PPU) writeVRAM(addr uint16, val byte) {
+	addr &= 0x3FFF
+	switch {
+	case addr < 0x2000:
+		ppu.mapper.ppuWrite(addr, val)
+	case addr < 0x3F00:
+		ppu.nametables[ppu.nametableAddress(addr)] = val
+	default:
+		ppu.palette[paletteAddress(addr)] = val & 0x3F
+	}
+}
+
+/*
+===============================================================================
+				Scrolling
+===============================================================================
+*/
+
+//incrementX ... Coarse X increment, wraps into the horizontally adjacent nametable
+func (ppu *PPU) incrementX() {
+	if ppu.v&0x001F == 31 {
+		ppu.v &^= 0x001F
+		ppu.v ^= 0x0400
+	} else {
+		ppu.v++
+	}
+}
+
+//incrementY ... Fine Y increment, overflowing into coarse Y. Row 29 wraps into
+//the vertically adjacent nametable, rows 30 and 31 (attribute data) wrap in place.
+func (ppu *PPU) incrementY() {
+	if ppu.v&0x7000 != 0x7000 {
+		ppu.v += 0x1000
+		return
+	}
+	ppu.v &^= 0x7000
+	y := (ppu.v & 0x03E0) >> 5
+	switch y {
+	case 29:
+		y = 0
+		ppu.v ^= 0x0800
+	case 31:
+		y = 0
+	default:
+		y++
+	}
+	ppu.v = (ppu.v &^ 0x03E0) | (y << 5)
+}
+
+func (ppu *PPU) copyX() {
+	ppu.v = (ppu.v & 0xFBE0) | (ppu.t & 0x041F)
+}
+
+func (ppu *PPU) copyY() {
+	ppu.v = (ppu.v & 0x841F) | (ppu.t & 0x7BE0)
+}
+
+/*
+===============================================================================
+				Rendering
+===============================================================================
+*/
+
+//Reference: https://wiki.nesdev.com/w/index.php/PPU_rendering
+
+//step ... Advances the PPU by one dot
+func (ppu *PPU) step() {
+	visibleLine := ppu.ScanLine < visibleLines
+	renderLine := visibleLine || ppu.ScanLine == preRenderLine
+	dot := ppu.Cycle
+
+	if ppu.renderingEnabled() && renderLine {
+		ppu.renderDot(dot)
+		if visibleLine && dot >= 1 && dot <= ScreenWidth {
+			ppu.renderPixel(dot-1, ppu.ScanLine)
+		}
+	} else if visibleLine && dot >= 1 && dot <= ScreenWidth {
+		ppu.back[ppu.ScanLine*ScreenWidth+dot-1] = ppu.readVRAM(0x3F00)
+	}
+
+	switch {
+	case ppu.ScanLine == vblankLine && dot == 1:
+		if !ppu.suppressVBlank {
+			ppu.status = setBit(ppu.status, 7)
+		}
+		ppu.front, ppu.back = ppu.back, ppu.front
+		ppu.frameReady = true
+	case ppu.ScanLine == preRenderLine && dot == 1:
+		ppu.status &^= 0xE0 // VBlank, sprite 0 hit and overflow
+		ppu.suppressVBlank = false
+	}
+
+	ppu.tick()
+}
+
+//renderDot ... Background and sprite fetches for one dot of a rendering scanline
+func (ppu *PPU) renderDot(dot int) {
+	bgFetch := (dot >= 1 && dot <= 256) || (dot >= 321 && dot <= 336)
+	if (dot >= 2 && dot <= 257) || (dot >= 322 && dot <= 337) {
+		ppu.shiftBackground()
+	}
+	if dot%8 == 1 && ((dot >= 9 && dot <= 257) || dot >= 329) {
+		ppu.loadBackground()
+	}
+	if bgFetch {
+		switch dot % 8 {
+		case 1:
+			ppu.fetchNametable()
+		case 3:
+			ppu.fetchAttribute()
+		case 5:
+			ppu.tileLo = ppu.readVRAM(ppu.backgroundAddress())
+		case 7:
+			ppu.tileHi = ppu.readVRAM(ppu.backgroundAddress() + 8)
+		case 0:
+			ppu.incrementX()
+		}
+	}
+
+	switch {
+	case dot == 256:
+		ppu.incrementY()
+	case dot == 257:
+		ppu.copyX()
+		ppu.evaluateSprites()
+	case dot >= 280 && dot <= 304 && ppu.ScanLine == preRenderLine:
+		ppu.copyY()
+	case dot == 337 || dot == 339:
+		ppu.fetchNametable()
+	}
+	if dot >= 257 && dot <= 320 {
+		ppu.oamAddr = 0
+		ppu.fetchSprite(dot - 257)
+	}
+}
+
+//tick ... Advances the dot counters. Odd frames skip the last dot of the
+//pre-render line while rendering is enabled.
+func (ppu *PPU) tick() {
+	if ppu.ScanLine == preRenderLine && ppu.Cycle == 339 && ppu.oddFrame && ppu.renderingEnabled() {
+		ppu.Cycle = 340
+	}
+	ppu.Cycle++
+	if ppu.Cycle < dotsPerLine {
+		return
+	}
+	ppu.Cycle = 0
+	ppu.ScanLine++
+	if ppu.ScanLine > preRenderLine {
+		ppu.ScanLine = 0
+		ppu.Frame++
+		ppu.oddFrame = !ppu.oddFrame
+	}
+}
+
+func (ppu *PPU) fetchNametable() {
+	ppu.ntByte = ppu.readVRAM(0x2000 | (ppu.v & 0x0FFF))
+}
+
+func (ppu *PPU) fetchAttribute() {
+	v := ppu.v
+	addr := 0x23C0 | (v & 0x0C00) | ((v >> 4) & 0x38) | ((v >> 2) & 0x07)
+	shift := ((v >> 4) & 0x04) | (v & 0x02)
+	ppu.atBits = (ppu.readVRAM(addr) >> shift) & 0x03
+}
+
+func (ppu *PPU) backgroundAddress() uint16 {
+	table := uint16(0)
+	if hasBit(ppu.ctrl, 4) {
+		table = patternTableSize
+	}
+	fineY := (ppu.v >> 12) & 0x07
+	return table + uint16(ppu.ntByte)*16 + fineY
+}
+
+//loadBackground ... Loads the fetched tile into the low byte of the shifters
+func (ppu *PPU) loadBackground() {
+	ppu.bgShiftLo = (ppu.bgShiftLo & 0xFF00) | uint16(ppu.tileLo)
+	ppu.bgShiftHi = (ppu.bgShiftHi & 0xFF00) | uint16(ppu.tileHi)
+	var lo, hi uint16
+	if hasBit(ppu.atBits, 0) {
+		lo = 0xFF
+	}
+	if hasBit(ppu.atBits, 1) {
+		hi = 0xFF
+	}
+	ppu.attrShiftLo = (ppu.attrShiftLo & 0xFF00) | lo
+	ppu.attrShiftHi = (ppu.attrShiftHi & 0xFF00) | hi
+}
+
+func (ppu *PPU) shiftBackground() {
+	ppu.bgShiftLo <<= 1
+	ppu.bgShiftHi <<= 1
+	ppu.attrShiftLo <<= 1
+	ppu.attrShiftHi <<= 1
+}
+
+//backgroundPixel ... 4 bit palette index of the background at the current dot
+func (ppu *PPU) backgroundPixel() byte {
+	if !hasBit(ppu.mask, 3) {
+		return 0
+	}
+	bit := 15 - uint16(ppu.x)
+	pixel := byte((ppu.bgShiftLo>>bit)&1) | byte((ppu.bgShiftHi>>bit)&1)<<1
+	if pixel == 0 {
+		return 0
+	}
+	attr := byte((ppu.attrShiftLo>>bit)&1) | byte((ppu.attrShiftHi>>bit)&1)<<1
+	return attr<<2 | pixel
+}
+
+//spritePixel ... Slot and 4 bit palette index of the frontmost opaque sprite at x
+func (ppu *PPU) spritePixel(x int) (int, byte) {
+	if !hasBit(ppu.mask, 4) {
+		return 0, 0
+	}
+	for i := 0; i < ppu.spriteCount; i++ {
+		offset := x - int(ppu.spriteX[i])
+		if offset < 0 || offset > 7 {
+			continue
+		}
+		bit := 7 - uint(offset)
+		pixel := (ppu.spritePatterns[i][0]>>bit)&1 | ((ppu.spritePatterns[i][1]>>bit)&1)<<1
+		if pixel == 0 {
+			continue
+		}
+		return i, (ppu.spriteAttr[i]&0x03)<<2 | pixel
+	}
+	return 0, 0
+}
+
+func (ppu *PPU) renderPixel(x, y int) {
+	bg := ppu.backgroundPixel()
+	slot, sprite := ppu.spritePixel(x)
+	if x < 8 && !hasBit(ppu.mask, 1) {
+		bg = 0
+	}
+	if x < 8 && !hasBit(ppu.mask, 2) {
+		sprite = 0
+	}
+
+	var color byte
+	switch {
+	case bg == 0 && sprite == 0:
+		color = 0
+	case bg == 0:
+		color = 0x10 | sprite
+	case sprite == 0:
+		color = bg
+	default:
+		if ppu.spriteIndex[slot] == 0 && x < 255 {
+			ppu.status = setBit(ppu.status, 6)
+		}
+		if hasBit(ppu.spriteAttr[slot], 5) { //behind background
+			color = bg
+		} else {
+			color = 0x10 | sprite
+		}
+	}
+	ppu.back[y*ScreenWidth+x] = ppu.readVRAM(0x3F00 | uint16(color))
+}
+
+func (ppu *PPU) spriteHeight() int {
+	if hasBit(ppu.ctrl, 5) {
+		return 16
+	}
+	return 8
+}
+
+//evaluateSprites ... Finds the first eight sprites on the next scanline.
+//The pre-render line finds none but still performs the pattern fetches.
+func (ppu *PPU) evaluateSprites() {
+	ppu.spriteCount = 0
+	if ppu.ScanLine == preRenderLine {
+		return
+	}
+	height := ppu.spriteHeight()
+	for i := 0; i < 64; i++ {
+		row := ppu.ScanLine - int(ppu.oam[i*4])
+		if row < 0 || row >= height {
+			continue
+		}
+		if ppu.spriteCount == maxLineSprites {
+			ppu.status = setBit(ppu.status, 5)
+			break
+		}
+		ppu.spriteIndex[ppu.spriteCount] = byte(i)
+		ppu.spriteCount++
+	}
+}
+
+//fetchSprite ... Sprite fetches during dots 257-320, eight dots per slot: two
+//garbage nametable reads followed by the pattern low and high bytes
+func (ppu *PPU) fetchSprite(n int) {
+	slot := n / 8
+	switch n % 8 {
+	case 0, 2:
+		ppu.readVRAM(0x2000 | (ppu.v & 0x0FFF))
+	case 4:
+		ppu.spritePatterns[slot][0] = ppu.readVRAM(ppu.spriteAddress(slot))
+	case 6:
+		ppu.spritePatterns[slot][1] = ppu.readVRAM(ppu.spriteAddress(slot) + 8)
+		ppu.latchSprite(slot)
+	}
+}
+
+//spriteAddress ... Pattern address of the sprite row for a slot. Empty slots
+//fetch tile $FF so the address lines still toggle like on hardware.
+func (ppu *PPU) spriteAddress(slot int) uint16 {
+	tile, attr, row := byte(0xFF), byte(0), 0
+	if slot < ppu.spriteCount {
+		sprite := ppu.oam[int(ppu.spriteIndex[slot])*4:]
+		row = ppu.ScanLine - int(sprite[0])
+		tile, attr = sprite[1], sprite[2]
+	}
+	height := ppu.spriteHeight()
+	if hasBit(attr, 7) {
+		row = height - 1 - row
+	}
+
+	if height == 8 {
+		table := uint16(0)
+		if hasBit(ppu.ctrl, 3) {
+			table = patternTableSize
+		}
+		return table + uint16(tile)*16 + uint16(row)
+	}
+	table := uint16(tile&1) * patternTableSize
+	tile &^= 1
+	if row > 7 {
+		tile++
+		row -= 8
+	}
+	return table + uint16(tile)*16 + uint16(row)
+}
+
+//latchSprite ... Loads attributes and X for a slot, flipping the pattern bytes
+//horizontally if needed. Empty slots get transparent patterns.
+func (ppu *PPU) latchSprite(slot int) {
+	if slot >= ppu.spriteCount {
+		ppu.spritePatterns[slot] = [2]byte{}
+		return
+	}
+	sprite := ppu.oam[int(ppu.spriteIndex[slot])*4:]
+	ppu.spriteAttr[slot] = sprite[2]
+	ppu.spriteX[slot] = sprite[3]
+	if hasBit(sprite[2], 6) {
+		ppu.spritePatterns[slot][0] = reverseBits(ppu.spritePatterns[slot][0])
+		ppu.spritePatterns[slot][1] = reverseBits(ppu.spritePatterns[slot][1])
+	}
+}
+
+/*
+===============================================================================
+				Save State
+===============================================================================
+*/
+
+//ppuState ... Serializable PPU state, including the partly drawn back buffer
+type ppuState struct {
+	Cycle      int
+	ScanLine   int
+	Frame      uint64
+	OddFrame   bool
+	Nametables []byte
+	Palette    []byte
+	OAM        []byte
+
+	Ctrl, Mask, Status, OAMAddr, ReadBuffer, Latch byte
+
+	V, T           uint16
+	X              byte
+	W              bool
+	SuppressVBlank bool
+	FrameReady     bool
+
+	NTByte, ATBits, TileLo, TileHi byte
+	BGShiftLo, BGShiftHi           uint16
+	AttrShiftLo, AttrShiftHi       uint16
+
+	SpriteCount    int
+	SpriteIndex    [maxLineSprites]byte
+	SpritePatterns [maxLineSprites][2]byte
+	SpriteX        [maxLineSprites]byte
+	SpriteAttr     [maxLineSprites]byte
+
+	Front, Back []byte
+}
+
+func (ppu *PPU) state() ppuState {
+	return ppuState{
+		Cycle:          ppu.Cycle,
+		ScanLine:       ppu.ScanLine,
+		Frame:          ppu.Frame,
+		OddFrame:       ppu.oddFrame,
+		Nametables:     ppu.nametables[:],
+		Palette:        ppu.palette[:],
+		OAM:            ppu.oam[:],
+		Ctrl:           ppu.ctrl,
+		Mask:           ppu.mask,
+		Status:         ppu.status,
+		OAMAddr:        ppu.oamAddr,
+		ReadBuffer:     ppu.readBuffer,
+		Latch:          ppu.latch,
+		V:              ppu.v,
+		T:              ppu.t,
+		X:              ppu.x,
+		W:              ppu.w,
+		SuppressVBlank: ppu.suppressVBlank,
+		FrameReady:     ppu.frameReady,
+		NTByte:         ppu.ntByte,
+		ATBits:         ppu.atBits,
+		TileLo:         ppu.tileLo,
+		TileHi:         ppu.tileHi,
+		BGShiftLo:      ppu.bgShiftLo,
+		BGShiftHi:      ppu.bgShiftHi,
+		AttrShiftLo:    ppu.attrShiftLo,
+		AttrShiftHi:    ppu.attrShiftHi,
+		SpriteCount:    ppu.spriteCount,
+		SpriteIndex:    ppu.spriteIndex,
+		SpritePatterns: ppu.spritePatterns,
+		SpriteX:        ppu.spriteX,
+		SpriteAttr:     ppu.spriteAttr,
+		Front:          ppu.front[:],
+		Back:           ppu.back[:],
+	}
+}
+
+func (ppu *PPU) restore(s ppuState) {
+	ppu.Cycle = s.Cycle
+	ppu.ScanLine = s.ScanLine
+	ppu.Frame = s.Frame
+	ppu.oddFrame = s.OddFrame
+	copy(ppu.nametables[:], s.Nametables)
+	copy(ppu.palette[:], s.Palette)
+	copy(ppu.oam[:], s.OAM)
+	ppu.ctrl = s.Ctrl
+	ppu.mask = s.Mask
+	ppu.status = s.Status
+	ppu.oamAddr = s.OAMAddr
+	ppu.readBuffer = s.ReadBuffer
+	ppu.latch = s.Latch
+	ppu.v = s.V
+	ppu.t = s.T
+	ppu.x = s.X
+	ppu.w = s.W
+	ppu.suppressVBlank = s.SuppressVBlank
+	ppu.frameReady = s.FrameReady
+	ppu.ntByte = s.NTByte
+	ppu.atBits = s.ATBits
+	ppu.tileLo = s.TileLo
+	ppu.tileHi = s.TileHi
+	ppu.bgShiftLo = s.BGShiftLo
+	ppu.bgShiftHi = s.BGShiftHi
+	ppu.attrShiftLo = s.AttrShiftLo
+	ppu.attrShiftHi = s.AttrShiftHi
+	ppu.spriteCount = s.SpriteCount
+	ppu.spriteIndex = s.SpriteIndex
+	ppu.spritePatterns = s.SpritePatterns
+	ppu.spriteX = s.SpriteX
+	ppu.spriteAttr = s.SpriteAttr
+	copy(ppu.front[:], s.Front)
+	copy(ppu.back[:], s.Back)
+}
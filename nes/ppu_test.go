@@ -0,0 +1,174 @@
+package nes
+
+import "testing"
+
+//TestPPUScrollRegisters ... The loopy v, t, x and w registers after writes to
+//$2000, $2005 and $2006 and reads of $2002
+//Reference: https://wiki.nesdev.com/w/index.php/PPU_scrolling#Register_controls
+func TestPPUScrollRegisters(t *testing.T) {
+	type access struct {
+		addr uint16
+		val  byte
+		read bool
+	}
+	tests := []struct {
+		name     string
+		accesses []access
+		v, t     uint16
+		x        byte
+		w        bool
+	}{
+		{
+			name:     "$2000 selects the nametable",
+			accesses: []access{{addr: 0x2000, val: 0x03}},
+			t:        0x0C00,
+		},
+		{
+			name:     "$2005 first write",
+			accesses: []access{{addr: 0x2005, val: 0x7D}},
+			t:        0x000F, x: 5, w: true,
+		},
+		{
+			name:     "$2005 second write",
+			accesses: []access{{addr: 0x2005, val: 0x7D}, {addr: 0x2005, val: 0x5E}},
+			t:        0x616F, x: 5,
+		},
+		{
+			name:     "$2000 keeps the scroll",
+			accesses: []access{{addr: 0x2005, val: 0x7D}, {addr: 0x2005, val: 0x5E}, {addr: 0x2000, val: 0x02}},
+			t:        0x696F, x: 5,
+		},
+		{
+			name:     "$2006 copies t to v on the second write",
+			accesses: []access{{addr: 0x2006, val: 0x3D}, {addr: 0x2006, val: 0xF0}},
+			v:        0x3DF0, t: 0x3DF0,
+		},
+		{
+			name:     "$2006 first write clears bit 14",
+			accesses: []access{{addr: 0x2005, val: 0x00}, {addr: 0x2005, val: 0xFF}, {addr: 0x2006, val: 0xFF}},
+			t:        0x3FE0, w: true,
+		},
+		{
+			name:     "$2006 first write leaves v alone",
+			accesses: []access{{addr: 0x2006, val: 0x21}},
+			t:        0x2100, w: true,
+		},
+		{
+			name:     "$2002 read resets the toggle",
+			accesses: []access{{addr: 0x2005, val: 0x7D}, {addr: 0x2002, read: true}, {addr: 0x2005, val: 0x5E}},
+			t:        0x000B, x: 6, w: true,
+		},
+		{
+			name: "$2005 and $2006 share the toggle",
+			accesses: []access{
+				{addr: 0x2006, val: 0x04}, {addr: 0x2005, val: 0x3E},
+				{addr: 0x2005, val: 0x7D}, {addr: 0x2006, val: 0xEF},
+			},
+			v: 0x64EF, t: 0x64EF, x: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ppu := newPPU(nil)
+			for _, a := range tt.accesses {
+				if a.read {
+					ppu.read(a.addr)
+				} else {
+					ppu.write(a.addr, a.val)
+				}
+			}
+			if ppu.v != tt.v || ppu.t != tt.t || ppu.x != tt.x || ppu.w != tt.w {
+				t.Errorf("v = $%04X, t = $%04X, x = %d, w = %v, want $%04X, $%04X, %d, %v",
+					ppu.v, ppu.t, ppu.x, ppu.w, tt.v, tt.t, tt.x, tt.w)
+			}
+		})
+	}
+}
+
+//mirroringMapper ... Mapper stub that only reports a mirroring arrangement
+type mirroringMapper struct {
+	Mapper
+	mirror Mirroring
+}
+
+func (m mirroringMapper) mirroring() Mirroring {
+	return m.mirror
+}
+
+//TestNametableMirroring ... Where each of the four logical nametables and
+//their mirror at $3000 land in nametable RAM
+func TestNametableMirroring(t *testing.T) {
+	tests := []struct {
+		name   string
+		mirror Mirroring
+		want   [4]uint16 //Physical address of $x123 in each logical nametable
+	}{
+		{name: "horizontal", mirror: MirrorHorizontal, want: [4]uint16{0x0123, 0x0123, 0x0523, 0x0523}},
+		{name: "vertical", mirror: MirrorVertical, want: [4]uint16{0x0123, 0x0523, 0x0123, 0x0523}},
+		{name: "single lower", mirror: MirrorSingleLower, want: [4]uint16{0x0123, 0x0123, 0x0123, 0x0123}},
+		{name: "single upper", mirror: MirrorSingleUpper, want: [4]uint16{0x0523, 0x0523, 0x0523, 0x0523}},
+		{name: "four-screen", mirror: MirrorFourScreen, want: [4]uint16{0x0123, 0x0523, 0x0923, 0x0D23}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ppu := newPPU(mirroringMapper{mirror: tt.mirror})
+			for i, want := range tt.want {
+				addr := 0x2123 + uint16(i)*0x0400
+				if got := ppu.nametableAddress(addr); got != want {
+					t.Errorf("$%04X maps to $%04X, want $%04X", addr, got, want)
+				}
+				if i < 3 {
+					if got := ppu.nametableAddress(addr + 0x1000); got != want {
+						t.Errorf("$%04X maps to $%04X, want $%04X", addr+0x1000, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+//TestVBlankNMI ... VBlank starts on dot 1 of line 241 and asserts NMI while
+//enabled in PPUCTRL, reading $2002 on the dot before suppresses it
+func TestVBlankNMI(t *testing.T) {
+	tests := []struct {
+		name    string
+		readDot int //Dot of line 241 $2002 is read before, or -1
+		ctrl    byte
+		vblank  bool
+		nmi     bool
+	}{
+		{name: "NMI enabled", readDot: -1, ctrl: 0x80, vblank: true, nmi: true},
+		{name: "NMI disabled", readDot: -1, vblank: true},
+		{name: "$2002 read the dot before", readDot: 1, ctrl: 0x80},
+		{name: "$2002 read two dots before", readDot: 0, ctrl: 0x80, vblank: true, nmi: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ppu := newPPU(nil)
+			ppu.ScanLine, ppu.Cycle = vblankLine-1, dotsPerLine-1
+			ppu.write(0x2000, tt.ctrl)
+			for ppu.ScanLine != vblankLine || ppu.Cycle != 2 {
+				if ppu.ScanLine == vblankLine && ppu.Cycle == tt.readDot {
+					ppu.read(0x2002)
+				}
+				if ppu.nmi() || hasBit(ppu.status, 7) {
+					t.Fatalf("VBlank started before dot 1, at dot %d", ppu.Cycle)
+				}
+				ppu.step()
+			}
+			if hasBit(ppu.status, 7) != tt.vblank || ppu.nmi() != tt.nmi {
+				t.Errorf("VBlank = %v, NMI = %v, want %v, %v", hasBit(ppu.status, 7), ppu.nmi(), tt.vblank, tt.nmi)
+			}
+
+			for ppu.ScanLine != preRenderLine || ppu.Cycle != 2 {
+				ppu.step()
+			}
+			if hasBit(ppu.status, 7) || ppu.nmi() {
+				t.Error("VBlank not cleared on the pre-render line")
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package nes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+const headerSize int = 16
+const trainerSize int = 512
+const kbSize int = 1024
+const prgUnitSize int = 16 * kbSize
+const chrUnitSize int = 8 * kbSize
+
+var inesMagic = []byte("NES\x1A")
+
+//ROM loading errors, wrapped with the details of what was wrong
+var (
+	ErrInvalidHeader = errors.New("invalid iNES header")
+	ErrTruncatedROM  = errors.New("ROM file is truncated")
+)
+
+//Mirroring ... Nametable mirroring arrangement
+type Mirroring byte
+
+//Nametable mirroring modes
+const (
+	MirrorHorizontal Mirroring = iota
+	MirrorVertical
+	MirrorSingleLower
+	MirrorSingleUpper
+	MirrorFourScreen
+)
+
+//Timing ... CPU/PPU timing region
+type Timing byte
+
+//CPU/PPU timing modes
+const (
+	TimingNTSC Timing = iota
+	TimingPAL
+	TimingMultiRegion
+	TimingDendy
+)
+
+//ConsoleType ... Console the ROM was built for
+type ConsoleType byte
+
+//Console types, NES 2.0 extended console types (header byte 13) share this numbering
+const (
+	ConsoleNES ConsoleType = iota
+	ConsoleVsSystem
+	ConsolePlaychoice10
+	ConsoleExtended
+)
+
+//ROM ... An iNES or NES 2.0 ROM image
+//Reference: https://wiki.nesdev.com/w/index.php/INES
+//Reference: https://wiki.nesdev.com/w/index.php/NES_2.0
+type ROM struct {
+	header          []byte
+	nes2            bool //Header is in NES 2.0 format
+	prgSize         int  //PRG ROM size in bytes
+	chrSize         int  //CHR ROM size in bytes, 0 means the board uses CHR RAM
+	prgRAMSize      int  //Volatile PRG RAM size in bytes
+	prgNVRAMSize    int  //Battery backed PRG RAM size in bytes
+	chrRAMSize      int  //Volatile CHR RAM size in bytes
+	chrNVRAMSize    int  //Battery backed CHR RAM size in bytes
+	trainer         bool
+	battery         bool
+	mirroring       Mirroring
+	mapper          uint16
+	submapper       byte
+	timing          Timing
+	consoleType     ConsoleType
+	vsHardware      byte //Vs. System PPU type (low nibble) and hardware type (high nibble)
+	expansionDevice byte //Default expansion device, 0 if unspecified
+	trainerData     []byte
+	prgROM          []byte
+	chrROM          []byte
+	data            []byte
+}
+
+func (rom *ROM) load() error {
+	if len(rom.data) < headerSize {
+		return fmt.Errorf("rom: %w: file is %d bytes, too short for a %d byte header", ErrTruncatedROM, len(rom.data), headerSize)
+	}
+	rom.header = rom.data[:headerSize]
+	if !bytes.Equal(rom.header[:4], inesMagic) {
+		return fmt.Errorf("rom: %w: bad magic % X, expected % X", ErrInvalidHeader, rom.header[:4], inesMagic)
+	}
+
+	flags6 := rom.header[6]
+	rom.trainer = hasBit(flags6, 2)
+	rom.battery = hasBit(flags6, 1)
+	switch {
+	case hasBit(flags6, 3):
+		rom.mirroring = MirrorFourScreen
+	case hasBit(flags6, 0):
+		rom.mirroring = MirrorVertical
+	default:
+		rom.mirroring = MirrorHorizontal
+	}
+
+	rom.nes2 = rom.header[7]&0x0C == 0x08
+	var err error
+	if rom.nes2 {
+		err = rom.loadNES2Header()
+	} else {
+		err = rom.loadINESHeader()
+	}
+	if err != nil {
+		return err
+	}
+	if rom.prgSize == 0 {
+		return fmt.Errorf("rom: %w: no PRG ROM", ErrInvalidHeader)
+	}
+
+	return rom.slice()
+}
+
+//loadINESHeader ... Decodes the fields specific to an iNES 1.0 header
+func (rom *ROM) loadINESHeader() error {
+	flags7, flags8, flags9 := rom.header[7], rom.header[8], rom.header[9]
+	// Old dumping tools wrote garbage such as "DiskDude!" into bytes 7-15,
+	// in which case none of bytes 7-9 can be trusted.
+	if !bytes.Equal(rom.header[12:16], []byte{0, 0, 0, 0}) {
+		flags7, flags8, flags9 = 0, 0, 0
+	}
+
+	rom.prgSize = int(rom.header[4]) * prgUnitSize
+	rom.chrSize = int(rom.header[5]) * chrUnitSize
+	rom.mapper = uint16((flags7 & 0xF0) | (rom.header[6] >> 4))
+	rom.consoleType = ConsoleType(flags7 & 0x03)
+
+	// iNES 1.0 can't describe RAM sizes, assume the common 8KB
+	ramSize := int(flags8) * chrUnitSize
+	if ramSize == 0 {
+		ramSize = chrUnitSize
+	}
+	if rom.battery {
+		rom.prgNVRAMSize = ramSize
+	} else {
+		rom.prgRAMSize = ramSize
+	}
+	if rom.chrSize == 0 {
+		rom.chrRAMSize = chrUnitSize
+	}
+	if hasBit(flags9, 0) {
+		rom.timing = TimingPAL
+	}
+	return nil
+}
+
+//loadNES2Header ... Decodes the fields specific to a NES 2.0 header
+func (rom *ROM) loadNES2Header() error {
+	h := rom.header
+	rom.mapper = uint16(h[8]&0x0F)<<8 | uint16(h[7]&0xF0) | uint16(h[6]>>4)
+	rom.submapper = h[8] >> 4
+
+	var err error
+	if rom.prgSize, err = nes2ROMSize(h[4], h[9]&0x0F, prgUnitSize); err != nil {
+		return fmt.Errorf("rom: PRG ROM size: %w", err)
+	}
+	if rom.chrSize, err = nes2ROMSize(h[5], h[9]>>4, chrUnitSize); err != nil {
+		return fmt.Errorf("rom: CHR ROM size: %w", err)
+	}
+	rom.prgRAMSize = nes2RAMSize(h[10] & 0x0F)
+	rom.prgNVRAMSize = nes2RAMSize(h[10] >> 4)
+	rom.chrRAMSize = nes2RAMSize(h[11] & 0x0F)
+	rom.chrNVRAMSize = nes2RAMSize(h[11] >> 4)
+
+	rom.timing = Timing(h[12] & 0x03)
+	rom.consoleType = ConsoleType(h[7] & 0x03)
+	switch rom.consoleType {
+	case ConsoleVsSystem:
+		rom.vsHardware = h[13]
+	case ConsoleExtended:
+		rom.consoleType = ConsoleType(h[13] & 0x0F)
+	}
+	rom.expansionDevice = h[15] & 0x3F
+	return nil
+}
+
+//nes2ROMSize ... Computes a NES 2.0 ROM size from its LSB and MSB nibble.
+//An MSB nibble of $F selects exponent-multiplier notation: 2^E * (MM*2+1)
+func nes2ROMSize(lsb, msb byte, unit int) (int, error) {
+	if msb != 0x0F {
+		return (int(msb)<<8 | int(lsb)) * unit, nil
+	}
+	exponent := uint(lsb >> 2)
+	multiplier := int(lsb&0x03)*2 + 1
+	if exponent > 30 {
+		return 0, fmt.Errorf("%w: exponent %d is too large", ErrInvalidHeader, exponent)
+	}
+	return (1 << exponent) * multiplier, nil
+}
+
+//nes2RAMSize ... Computes a NES 2.0 RAM size from its shift count, 64 << shift
+func nes2RAMSize(shift byte) int {
+	if shift == 0 {
+		return 0
+	}
+	return 64 << shift
+}
+
+//slice ... Splits the trainer, PRG ROM and CHR ROM out of the file body
+func (rom *ROM) slice() error {
+	offset := headerSize
+	need := offset + rom.prgSize + rom.chrSize
+	if rom.trainer {
+		need += trainerSize
+	}
+	if len(rom.data) < need {
+		return fmt.Errorf("rom: %w: header declares %d bytes but file has %d", ErrTruncatedROM, need, len(rom.data))
+	}
+
+	if rom.trainer {
+		rom.trainerData = rom.data[offset : offset+trainerSize]
+		offset += trainerSize
+	}
+	rom.prgROM = rom.data[offset : offset+rom.prgSize]
+	offset += rom.prgSize
+	rom.chrROM = rom.data[offset : offset+rom.chrSize]
+	return nil
+}
@@ -0,0 +1,229 @@
+package nes
+
+import (
+	"errors"
+	"testing"
+)
+
+//inesHeader ... A 16 byte header with the magic, bytes 4 onwards from b
+func inesHeader(b ...byte) []byte {
+	h := make([]byte, headerSize)
+	copy(h, inesMagic)
+	copy(h[4:], b)
+	return h
+}
+
+//loadImage ... Loads header followed by body bytes of zeroes
+func loadImage(header []byte, body int) (*ROM, error) {
+	rom := &ROM{data: append(header, make([]byte, body)...)}
+	return rom, rom.load()
+}
+
+//TestLoadINESHeader ... Fields decoded from iNES 1.0 headers
+func TestLoadINESHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		body   int
+		want   ROM
+	}{
+		{
+			name:   "NROM-256",
+			header: inesHeader(2, 1, 0x01),
+			body:   2*prgUnitSize + chrUnitSize,
+			want: ROM{prgSize: 2 * prgUnitSize, chrSize: chrUnitSize, mirroring: MirrorVertical,
+				prgRAMSize: chrUnitSize},
+		},
+		{
+			name:   "mapper nibbles, battery and CHR RAM",
+			header: inesHeader(8, 0, 0x42, 0x10),
+			body:   8 * prgUnitSize,
+			want: ROM{prgSize: 8 * prgUnitSize, mapper: 0x14, battery: true,
+				prgNVRAMSize: chrUnitSize, chrRAMSize: chrUnitSize},
+		},
+		{
+			name:   "trainer, four screen, PRG RAM and PAL",
+			header: inesHeader(1, 1, 0x0C, 0x00, 4, 1),
+			body:   trainerSize + prgUnitSize + chrUnitSize,
+			want: ROM{prgSize: prgUnitSize, chrSize: chrUnitSize, trainer: true,
+				mirroring: MirrorFourScreen, prgRAMSize: 4 * chrUnitSize, timing: TimingPAL},
+		},
+		{
+			name:   "Vs. System",
+			header: inesHeader(2, 2, 0x00, 0x01),
+			body:   2*prgUnitSize + 2*chrUnitSize,
+			want: ROM{prgSize: 2 * prgUnitSize, chrSize: 2 * chrUnitSize, consoleType: ConsoleVsSystem,
+				prgRAMSize: chrUnitSize},
+		},
+		{
+			// bytes 7-15 hold "DiskDude!", which would read as mapper $41,
+			// 105 banks of PRG RAM and PAL timing
+			name:   "DiskDude! garbage ignored",
+			header: inesHeader(1, 1, 0x10, 'D', 'i', 's', 'k', 'D', 'u', 'd', 'e', '!'),
+			body:   prgUnitSize + chrUnitSize,
+			want: ROM{prgSize: prgUnitSize, chrSize: chrUnitSize, mapper: 1,
+				prgRAMSize: chrUnitSize},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rom, err := loadImage(tt.header, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rom.nes2 {
+				t.Error("detected as NES 2.0")
+			}
+			checkROM(t, rom, &tt.want)
+		})
+	}
+}
+
+//checkROM ... Compares the decoded header fields of got against want
+func checkROM(t *testing.T, got, want *ROM) {
+	t.Helper()
+	check := func(field string, g, w interface{}) {
+		if g != w {
+			t.Errorf("%s = %v, want %v", field, g, w)
+		}
+	}
+	check("prgSize", got.prgSize, want.prgSize)
+	check("chrSize", got.chrSize, want.chrSize)
+	check("prgRAMSize", got.prgRAMSize, want.prgRAMSize)
+	check("prgNVRAMSize", got.prgNVRAMSize, want.prgNVRAMSize)
+	check("chrRAMSize", got.chrRAMSize, want.chrRAMSize)
+	check("chrNVRAMSize", got.chrNVRAMSize, want.chrNVRAMSize)
+	check("trainer", got.trainer, want.trainer)
+	check("battery", got.battery, want.battery)
+	check("mirroring", got.mirroring, want.mirroring)
+	check("mapper", got.mapper, want.mapper)
+	check("submapper", got.submapper, want.submapper)
+	check("timing", got.timing, want.timing)
+	check("consoleType", got.consoleType, want.consoleType)
+	check("vsHardware", got.vsHardware, want.vsHardware)
+	check("expansionDevice", got.expansionDevice, want.expansionDevice)
+}
+
+//TestLoadNES2Header ... Fields decoded from NES 2.0 headers
+func TestLoadNES2Header(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		body   int
+		want   ROM
+	}{
+		{
+			name:   "12 bit mapper and submapper",
+			header: inesHeader(2, 1, 0x41, 0x58, 0x31),
+			body:   2*prgUnitSize + chrUnitSize,
+			want:   ROM{prgSize: 2 * prgUnitSize, chrSize: chrUnitSize, mirroring: MirrorVertical, mapper: 0x154, submapper: 3},
+		},
+		{
+			name:   "size MSB nibbles",
+			header: inesHeader(0x00, 0x02, 0x00, 0x08, 0x00, 0x11),
+			body:   0x100*prgUnitSize + 0x102*chrUnitSize,
+			want:   ROM{prgSize: 0x100 * prgUnitSize, chrSize: 0x102 * chrUnitSize},
+		},
+		{
+			// PRG is 2^13 * 1 = 8KB, CHR is 2^10 * 3 = 3KB
+			name:   "exponent-multiplier sizes",
+			header: inesHeader(0x34, 0x29, 0x00, 0x08, 0x00, 0xFF),
+			body:   8*kbSize + 3*kbSize,
+			want:   ROM{prgSize: 8 * kbSize, chrSize: 3 * kbSize},
+		},
+		{
+			name:   "RAM shift counts",
+			header: inesHeader(1, 0, 0x02, 0x08, 0x00, 0x00, 0x97, 0x07),
+			body:   prgUnitSize,
+			want: ROM{prgSize: prgUnitSize, battery: true,
+				prgRAMSize: 64 << 7, prgNVRAMSize: 64 << 9, chrRAMSize: 64 << 7},
+		},
+		{
+			name:   "timing and expansion device",
+			header: inesHeader(1, 1, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x01),
+			body:   prgUnitSize + chrUnitSize,
+			want:   ROM{prgSize: prgUnitSize, chrSize: chrUnitSize, timing: TimingDendy, expansionDevice: 1},
+		},
+		{
+			name:   "Vs. System hardware",
+			header: inesHeader(1, 1, 0x00, 0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x21),
+			body:   prgUnitSize + chrUnitSize,
+			want:   ROM{prgSize: prgUnitSize, chrSize: chrUnitSize, consoleType: ConsoleVsSystem, vsHardware: 0x21},
+		},
+		{
+			name:   "extended console type",
+			header: inesHeader(1, 1, 0x00, 0x0B, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03),
+			body:   prgUnitSize + chrUnitSize,
+			want:   ROM{prgSize: prgUnitSize, chrSize: chrUnitSize, consoleType: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rom, err := loadImage(tt.header, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !rom.nes2 {
+				t.Error("not detected as NES 2.0")
+			}
+			checkROM(t, rom, &tt.want)
+		})
+	}
+}
+
+//TestNES2ROMSize ... Both size notations
+func TestNES2ROMSize(t *testing.T) {
+	tests := []struct {
+		lsb, msb byte
+		unit     int
+		want     int
+	}{
+		{lsb: 0x00, msb: 0x0, unit: prgUnitSize, want: 0},
+		{lsb: 0x20, msb: 0x0, unit: prgUnitSize, want: 0x20 * prgUnitSize},
+		{lsb: 0x01, msb: 0xE, unit: chrUnitSize, want: 0xE01 * chrUnitSize},
+		{lsb: 0x00, msb: 0xF, unit: prgUnitSize, want: 1},
+		{lsb: 0x03, msb: 0xF, unit: prgUnitSize, want: 7},
+		{lsb: 0x4D, msb: 0xF, unit: prgUnitSize, want: 1 << 19 * 3},
+		{lsb: 0x7B, msb: 0xF, unit: chrUnitSize, want: 1 << 30 * 7},
+	}
+
+	for _, tt := range tests {
+		got, err := nes2ROMSize(tt.lsb, tt.msb, tt.unit)
+		if err != nil {
+			t.Errorf("nes2ROMSize($%02X, $%X): %v", tt.lsb, tt.msb, err)
+		} else if got != tt.want {
+			t.Errorf("nes2ROMSize($%02X, $%X) = %d, want %d", tt.lsb, tt.msb, got, tt.want)
+		}
+	}
+	if _, err := nes2ROMSize(0xFC, 0xF, prgUnitSize); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("exponent 63: got %v, want ErrInvalidHeader", err)
+	}
+}
+
+//TestLoadErrors ... Malformed images are rejected with the matching error
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		body   int
+		want   error
+	}{
+		{name: "short header", header: inesHeader(1, 1)[:10], want: ErrTruncatedROM},
+		{name: "bad magic", header: append([]byte("NES\x00"), inesHeader(1, 1)[4:]...), body: prgUnitSize + chrUnitSize, want: ErrInvalidHeader},
+		{name: "no PRG ROM", header: inesHeader(0, 1), body: chrUnitSize, want: ErrInvalidHeader},
+		{name: "truncated PRG ROM", header: inesHeader(2, 0), body: prgUnitSize, want: ErrTruncatedROM},
+		{name: "truncated CHR ROM", header: inesHeader(1, 1), body: prgUnitSize + chrUnitSize - 1, want: ErrTruncatedROM},
+		{name: "missing trainer", header: inesHeader(1, 1, 0x04), body: prgUnitSize + chrUnitSize, want: ErrTruncatedROM},
+		{name: "NES 2.0 exponent too large", header: inesHeader(0xFC, 0, 0x00, 0x08, 0x00, 0x0F), want: ErrInvalidHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadImage(tt.header, tt.body); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package nes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+//ErrStateMismatch ... The save state was taken on a console running another ROM
+var ErrStateMismatch = errors.New("nes: save state is for a different ROM")
+
+//ErrMidInstruction ... A cycle-stepped console was stopped by Tick in the
+//middle of an instruction, where it can't be saved
+var ErrMidInstruction = errors.New("nes: can't save state in the middle of an instruction")
+
+//consoleState ... Everything a save state holds. The ROM isn't saved, only its
+//checksum to tell which ROM the state belongs to.
+type consoleState struct {
+	ROM         uint32 //CRC-32 of the ROM image
+	CPU         cpuState
+	PPU         ppuState
+	APU         apuState
+	RAM         []byte
+	BusLatch    byte
+	Controllers [2]controllerState
+	PolledFrame uint64
+	OwedCycles  int
+	Mapper      []byte //Written by the mapper's saveState
+}
+
+//SaveState ... Writes a snapshot of the console, which LoadState restores on a
+//console running the same ROM. A cycle-stepped console left in the middle of an
+//instruction by Tick has to finish it with StepInstruction first.
+func (nes *NES) SaveState(w io.Writer) error {
+	if nes.midInstruction {
+		return ErrMidInstruction
+	}
+	var mapper bytes.Buffer
+	if err := nes.mapper.saveState(&mapper); err != nil {
+		return fmt.Errorf("nes: saving mapper state: %w", err)
+	}
+	s := consoleState{
+		ROM:         crc32.ChecksumIEEE(nes.rom.data),
+		CPU:         nes.cpu.state(),
+		PPU:         nes.ppu.state(),
+		APU:         nes.apu.state(),
+		RAM:         nes.bus.ram[:],
+		BusLatch:    nes.bus.latch,
+		PolledFrame: nes.polledFrame,
+		OwedCycles:  nes.owedCycles,
+		Mapper:      mapper.Bytes(),
+	}
+	for port := range nes.bus.controllers {
+		s.Controllers[port] = nes.bus.controllers[port].state()
+	}
+	return encodeState(w, s)
+}
+
+//LoadState ... Restores a snapshot written by SaveState. The console is left
+//untouched if the state can't be read or was saved on another ROM.
+func (nes *NES) LoadState(r io.Reader) error {
+	if nes.closed {
+		return ErrClosed
+	}
+	var s consoleState
+	if err := decodeState(r, &s); err != nil {
+		return fmt.Errorf("nes: reading state: %w", err)
+	}
+	if s.ROM != crc32.ChecksumIEEE(nes.rom.data) {
+		return ErrStateMismatch
+	}
+	if err := nes.mapper.loadState(bytes.NewReader(s.Mapper)); err != nil {
+		return fmt.Errorf("nes: reading mapper state: %w", err)
+	}
+
+	if nes.core != nil {
+		// abandon the instruction in progress, the coroutine restarts at the
+		// instruction boundary the state was saved on
+		nes.core.detach()
+		defer nes.core.attach()
+	}
+	nes.cpu.restore(s.CPU)
+	nes.ppu.restore(s.PPU)
+	nes.apu.restore(s.APU)
+	copy(nes.bus.ram[:], s.RAM)
+	nes.bus.latch = s.BusLatch
+	for port := range nes.bus.controllers {
+		nes.bus.controllers[port].restore(s.Controllers[port])
+	}
+	nes.polledFrame = s.PolledFrame
+	nes.owedCycles = s.OwedCycles
+	nes.midInstruction = false
+	if nes.core != nil {
+		// the cycle-stepped core doesn't run ahead, catch up on the cycles
+		// an instruction-stepped console still owed
+		nes.clockOwed()
+	}
+	return nil
+}
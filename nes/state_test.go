@@ -0,0 +1,147 @@
+package nes
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+//stateProgram ... Renders with NMI on, plays pulse 1 and changes the backdrop
+//colour and pulse period every frame, so the PPU and APU both carry state
+var stateProgram = []byte{
+	0xA9, 0x1E, //$C000 LDA #$1E
+	0x8D, 0x01, 0x20, //STA $2001
+	0xA9, 0x80, //LDA #$80
+	0x8D, 0x00, 0x20, //STA $2000
+	0xA9, 0x01, //LDA #$01
+	0x8D, 0x15, 0x40, //STA $4015
+	0xA9, 0xBF, //LDA #$BF
+	0x8D, 0x00, 0x40, //STA $4000
+	0xA9, 0x09, //LDA #$09
+	0x8D, 0x03, 0x40, //STA $4003
+	0xE8,             //$C019 INX
+	0x8E, 0x00, 0x03, //STX $0300
+	0x4C, 0x19, 0xC0, //JMP $C019
+	0xE6, 0x10, //$C020 NMI: INC $10
+	0xA9, 0x3F, //LDA #$3F
+	0x8D, 0x06, 0x20, //STA $2006
+	0xA9, 0x00, //LDA #$00
+	0x8D, 0x06, 0x20, //STA $2006
+	0xA5, 0x10, //LDA $10
+	0x8D, 0x07, 0x20, //STA $2007
+	0x8D, 0x02, 0x40, //STA $4002
+	0x40, //RTI
+}
+
+//runFrames ... Runs a console for a number of frames and returns copies of the
+//frames and all audio samples
+func runFrames(t *testing.T, console *NES, frames int) ([]Frame, []float32) {
+	t.Helper()
+	var out []Frame
+	var audio []float32
+	for i := 0; i < frames; i++ {
+		frame, samples, err := console.RunFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, *frame)
+		audio = append(audio, samples...)
+	}
+	return out, audio
+}
+
+//TestSaveState ... A console restored from a state taken in the middle of a
+//frame continues exactly like the console the state was taken from
+func TestSaveState(t *testing.T) {
+	image := nromImage(stateProgram, 0xC020)
+	for _, cycleStepped := range []bool{false, true} {
+		opts := Options{CycleStepped: cycleStepped}
+		console, err := New(image, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer console.Close()
+		runFrames(t, console, 3)
+		if _, err := console.StepCycles(10000); err != nil {
+			t.Fatal(err)
+		}
+		console.AudioSamples() //samples not drained yet aren't part of a state
+		var state bytes.Buffer
+		if err := console.SaveState(&state); err != nil {
+			t.Fatal(err)
+		}
+		wantFrames, wantAudio := runFrames(t, console, 3)
+
+		restored, err := New(image, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer restored.Close()
+		runFrames(t, restored, 1)
+		if err := restored.LoadState(&state); err != nil {
+			t.Fatal(err)
+		}
+		frames, audio := runFrames(t, restored, 3)
+
+		if !reflect.DeepEqual(frames, wantFrames) {
+			t.Error("restored console drew different frames")
+		}
+		if !reflect.DeepEqual(audio, wantAudio) {
+			t.Errorf("restored console played different audio, %d samples, want %d", len(audio), len(wantAudio))
+		}
+		if restored.cpu.state() != console.cpu.state() {
+			t.Errorf("CPU state\n%+v\nwant\n%+v", restored.cpu.state(), console.cpu.state())
+		}
+	}
+}
+
+//TestSaveStateMidInstruction ... A cycle-stepped console can only be saved
+//between instructions
+func TestSaveStateMidInstruction(t *testing.T) {
+	console, err := New(nromImage(stateProgram, 0xC020), Options{CycleStepped: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer console.Close()
+	if err := console.Tick(); err != nil {
+		t.Fatal(err)
+	}
+	if err := console.SaveState(&bytes.Buffer{}); !errors.Is(err, ErrMidInstruction) {
+		t.Fatalf("got %v, want ErrMidInstruction", err)
+	}
+	if _, err := console.StepInstruction(); err != nil {
+		t.Fatal(err)
+	}
+	if err := console.SaveState(&bytes.Buffer{}); err != nil {
+		t.Errorf("got %v after finishing the instruction", err)
+	}
+}
+
+//TestLoadStateErrors ... States from another ROM or that can't be decoded are
+//rejected and leave the console as it was
+func TestLoadStateErrors(t *testing.T) {
+	console, err := New(nromImage(stateProgram, 0xC020), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state bytes.Buffer
+	if err := console.SaveState(&state); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := New(nromImage([]byte{0xEA}, 0xC000), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := other.cpu.state()
+	if err := other.LoadState(bytes.NewReader(state.Bytes())); !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("got %v, want ErrStateMismatch", err)
+	}
+	if err := other.LoadState(bytes.NewReader(state.Bytes()[:100])); err == nil {
+		t.Error("truncated state loaded")
+	}
+	if other.cpu.state() != before {
+		t.Error("failed loads changed the console")
+	}
+}
@@ -0,0 +1,75 @@
+package nes
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+//Tracer ... Logs each instruction before it executes, in the format of
+//Nintendulator and the nestest golden log:
+//
+//C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD PPU:  0, 21 CYC:7
+//
+//Tracing is off unless a Tracer is passed in Options or to SetTracer.
+type Tracer struct {
+	w   io.Writer
+	err error //First write error, tracing stops after it
+
+	pcLow, pcHigh         uint16
+	firstFrame, lastFrame uint64
+}
+
+//NewTracer ... Creates a tracer writing to w that logs every instruction
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{
+		w:         w,
+		pcHigh:    0xFFFF,
+		lastFrame: math.MaxUint64,
+	}
+}
+
+//SetPCRange ... Only log instructions at addresses from low to high inclusive
+func (t *Tracer) SetPCRange(low, high uint16) {
+	t.pcLow, t.pcHigh = low, high
+}
+
+//SetFrameRange ... Only log instructions executed while the PPU frame count is
+//from first to last inclusive
+func (t *Tracer) SetFrameRange(first, last uint64) {
+	t.firstFrame, t.lastFrame = first, last
+}
+
+//Err ... The error that stopped tracing, if writing to the log failed
+func (t *Tracer) Err() error {
+	return t.err
+}
+
+func (t *Tracer) enabled(pc uint16, frame uint64) bool {
+	return t.err == nil &&
+		pc >= t.pcLow && pc <= t.pcHigh &&
+		frame >= t.firstFrame && frame <= t.lastFrame
+}
+
+//trace ... Writes the log line for the instruction about to execute
+func (t *Tracer) trace(cpu *CPU, ppu *PPU, b *Bus) {
+	if !t.enabled(cpu.PC, ppu.Frame) {
+		return
+	}
+	raw, text := disassemble(cpu, b, cpu.PC)
+	// unofficial opcodes are flagged by a '*' in the column before the mnemonic
+	if text[0] != '*' {
+		text = " " + text
+	}
+	_, t.err = fmt.Fprintf(t.w, "%04X  %-8s %-32s A:%02X X:%02X Y:%02X P:%02X SP:%02X PPU:%3d,%3d CYC:%d\n",
+		cpu.PC, raw, text, cpu.A, cpu.X, cpu.Y, cpu.P, cpu.SP, ppu.ScanLine, ppu.Cycle, cpu.Cycles)
+}
+
+//SetTracer ... Starts logging instructions to t, or stops logging if t is nil
+func (nes *NES) SetTracer(t *Tracer) {
+	if t == nil {
+		nes.cpu.trace = nil
+		return
+	}
+	nes.cpu.trace = func() { t.trace(&nes.cpu, nes.ppu, nes.bus) }
+}
@@ -0,0 +1,51 @@
+package nes
+
+import "testing"
+
+//TestTracerRanges ... Only instructions inside both the PC range and the frame
+//range are logged
+func TestTracerRanges(t *testing.T) {
+	const nmi = 0xC020 //stateProgram's NMI handler
+	tests := []struct {
+		name                  string
+		pcLow, pcHigh         uint16
+		firstFrame, lastFrame uint64
+	}{
+		{name: "everything", pcHigh: 0xFFFF, lastFrame: 4},
+		{name: "NMI handler", pcLow: nmi, pcHigh: 0xC033, lastFrame: 4},
+		{name: "main loop", pcLow: 0xC019, pcHigh: 0xC01F, lastFrame: 4},
+		{name: "frames 2-3", pcHigh: 0xFFFF, firstFrame: 2, lastFrame: 3},
+		{name: "NMI handler in frame 3", pcLow: nmi, pcHigh: 0xC033, firstFrame: 3, lastFrame: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &lineWriter{}
+			tracer := NewTracer(out)
+			tracer.SetPCRange(tt.pcLow, tt.pcHigh)
+			tracer.SetFrameRange(tt.firstFrame, tt.lastFrame)
+			console, err := New(nromImage(stateProgram, nmi), Options{Tracer: tracer})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for console.PPU().Frame < 5 {
+				pc, frame, lines := console.CPU().PC, console.PPU().Frame, len(out.lines)
+				if _, err := console.StepInstruction(); err != nil {
+					t.Fatal(err)
+				}
+				if console.CPU().PC == nmi {
+					continue //the NMI sequence isn't an instruction
+				}
+				logged := len(out.lines) > lines
+				want := pc >= tt.pcLow && pc <= tt.pcHigh && frame >= tt.firstFrame && frame <= tt.lastFrame
+				if logged != want {
+					t.Fatalf("$%04X in frame %d: logged = %v, want %v", pc, frame, logged, want)
+				}
+			}
+			if len(out.lines) == 0 {
+				t.Error("nothing logged")
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package nes
+
+import "io"
+
+//UxROM ... Mapper 2, switchable 16KB PRG bank at $8000, last bank fixed at $C000
+//Reference: https://wiki.nesdev.com/w/index.php/UxROM
+type UxROM struct {
+	board
+	prgBank byte
+}
+
+func newUxROM(rom *ROM) (*UxROM, error) {
+	m := &UxROM{board: newBoard(rom)}
+	if err := m.checkBankSizes("UxROM", prgUnitSize, 0); err != nil {
+		return nil, err
+	}
+	m.busConflicts = hasBusConflicts(rom)
+	return m, nil
+}
+
+func (m *UxROM) cpuRead(addr uint16) byte {
+	switch {
+	case addr >= 0xC000:
+		return m.prgROM[len(m.prgROM)-prgUnitSize+int(addr-0xC000)]
+	case addr >= 0x8000:
+		banks := len(m.prgROM) / prgUnitSize
+		return m.prgROM[int(m.prgBank)%banks*prgUnitSize+int(addr-0x8000)]
+	case addr >= 0x6000:
+		return m.readPRGRAM(addr)
+	}
+	return 0
+}
+
+func (m *UxROM) cpuWrite(addr uint16, val byte) {
+	switch {
+	case addr >= 0x8000:
+		m.prgBank = m.conflict(m, addr, val)
+	case addr >= 0x6000:
+		m.writePRGRAM(addr, val)
+	}
+}
+
+func (m *UxROM) ppuRead(addr uint16) byte {
+	return m.chr[int(addr)%len(m.chr)]
+}
+
+func (m *UxROM) ppuWrite(addr uint16, val byte) {
+	if m.chrRAM {
+		m.chr[int(addr)%len(m.chr)] = val
+	}
+}
+
+func (m *UxROM) mirroring() Mirroring {
+	return m.mirror
+}
+
+func (m *UxROM) irq() bool {
+	return false
+}
+
+type uxromState struct {
+	Board   boardState
+	PRGBank byte
+}
+
+func (m *UxROM) saveState(w io.Writer) error {
+	return encodeState(w, uxromState{Board: m.state(), PRGBank: m.prgBank})
+}
+
+func (m *UxROM) loadState(r io.Reader) error {
+	var s uxromState
+	if err := decodeState(r, &s); err != nil {
+		return err
+	}
+	m.restore(s.Board)
+	m.prgBank = s.PRGBank
+	return nil
+}